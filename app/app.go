@@ -2,11 +2,13 @@ package app
 
 import (
 	"fmt"
-	"math"
+	"log"
+	"path"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/cache"
 	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
 	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
 	"github.com/awcjack/samknows-backend-code-test/types"
@@ -15,14 +17,86 @@ import (
 type Application struct {
 	reader reader.Reader
 	writer writer.Writer
+	config Config
+	// cache of inputs already analysed, nil to analyse every input
+	cache cache.Cache
+	force bool
+	// owner input of outputs planned by earlier runs, nil to find collision inside one run only
+	tracked *outputOwners
+}
+
+// options of the analysis, zero value produce the original report
+type Config struct {
+	// calendar bucket that statistics table is rendered for
+	Aggregations []Aggregation
+	// peak hours window to compare against off-peak hours, nil to disable
+	Peak *PeakWindow
+	// timezone that dates are reported and bucketed in, nil means UTC
+	Location *time.Location
+	// timezone override keyed by input name
+	InputLocations map[string]*time.Location
+	// rule that under-performing mesurement is found by
+	Outlier Outlier
+	// unit that values are reported in, zero value choose the unit automatically
+	Unit Unit
+	// report formats written for every input, empty means text only
+	Formats []Format
+	// template replacing the default template of format
+	Templates map[Format]Template
+	// how output names are derived from input names
+	Naming Naming
 }
 
 // function to make new application with reader and writer (using interfae to provide flexibility to switch to other reader or writer like database easily)
 func NewApplication(reader reader.Reader, writer writer.Writer) Application {
+	return NewApplicationWithConfig(reader, writer, Config{})
+}
+
+// function to make new application with reader, writer and analysis options
+func NewApplicationWithConfig(reader reader.Reader, writer writer.Writer, config Config) Application {
 	return Application{
 		reader: reader,
 		writer: writer,
+		config: config,
+	}
+}
+
+// unit that report values are rendered in
+type Unit struct {
+	name     string
+	exponent int
+}
+
+var unitSymbols = []string{"bps", "kbps", "mbps", "gbps", "tbps", "pbps"}
+var unitNames = []string{"Bits per second", "Kilobits per second", "Megabits per second", "Gigabits per second", "Terabits per second", "Petabits per second"}
+
+// function to parse unit symbol provided by user (bps, kbps, mbps, gbps, tbps, pbps or auto)
+func ParseUnit(symbol string) (Unit, error) {
+	symbol = strings.ToLower(symbol)
+	if symbol == "auto" || symbol == "" {
+		return Unit{}, nil
+	}
+
+	for i, unitSymbol := range unitSymbols {
+		if unitSymbol == symbol {
+			return Unit{name: unitNames[i], exponent: i}, nil
+		}
 	}
+
+	return Unit{}, fmt.Errorf("unknown unit %q (expected auto, %s)", symbol, strings.Join(unitSymbols, ", "))
+}
+
+// function to find the value that automatic unit is chosen for, the smallest non-zero value shown in report
+// Min is 0 when the series has outage, and 0 is 0.00 in any unit, so it does not force the report into bits per second
+func (a Application) findUnitValue(statistics types.Statistics) float64 {
+	result := 0.0
+	for _, value := range []float64{statistics.Min, statistics.Max, statistics.Median, statistics.Mean} {
+		if value > 0 && (result == 0 || value < result) {
+			result = value
+		}
+	}
+
+	return result
 }
 
 // Always use ____bits per second unit to prevent confussion
@@ -65,131 +139,183 @@ func (a Application) findOptimalUnit(min float64) (string, int) {
 	return result, time
 }
 
-// function to find min, max, average from dataset
-func (a Application) findMinMaxMean(input []types.Mesurement) (float64, float64, float64) {
-	var min float64 = 0
-	var max float64 = 0
-	var sum float64 = 0
+// function to find period that under performance (value below the lower fence of the outlier rule, Q1 - 1.5 * IQR by default) from the summarised series
+// only timestamps of under-performing mesurements are localised (kept as is when location is nil)
+func (a Application) findUnderPerformance(input []types.Mesurement, s *series, lowerFence float64, location *time.Location) []time.Time {
+	positions := s.below(lowerFence)
+	result := make([]time.Time, 0, len(positions))
 
-	if len(input) == 0 {
-		return 0, 0, 0
+	for _, i := range positions {
+		if location != nil {
+			result = append(result, input[i].Dtime.Localise(location).Time)
+		} else {
+			result = append(result, input[i].Dtime.Time)
+		}
 	}
 
-	for _, mesurement := range input {
-		if mesurement.MetricValue < min || min == 0 {
-			min = mesurement.MetricValue
-		}
+	return result
+}
+
+// function to reduce timestamps to distinct dates in ascending order (mesurement may have time of day)
+func (a Application) findUniqueDates(times []time.Time) []time.Time {
+	// keyed by calendar date so start of the date is only computed once per date instead of once per time
+	type key struct {
+		year     int
+		month    time.Month
+		day      int
+		location *time.Location
+	}
+	seen := make(map[key]bool)
+	result := make([]time.Time, 0)
 
-		if mesurement.MetricValue > max {
-			max = mesurement.MetricValue
+	for _, t := range times {
+		year, month, day := t.Date()
+		k := key{year: year, month: month, day: day, location: t.Location()}
+		if seen[k] {
+			continue
 		}
+		seen[k] = true
 
-		sum += mesurement.MetricValue
+		date := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+		// midnight skipped by daylight saving time change (e.g. America/Sao_Paulo) is normalised into previous date, so take first hour of the date instead
+		for !sameDate(date, t) {
+			date = date.Add(time.Hour)
+		}
+		result = append(result, date)
 	}
 
-	return min, max, (sum / float64(len(input)))
-}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Before(result[j])
+	})
 
-// function to find median, first quartile and IQR from dataset
-func (a Application) findMedianFirstQuartileIQR(input []types.Mesurement) (float64, float64, float64) {
-	floatArray := make([]float64, 0, len(input))
+	return result
+}
 
-	for _, mesurement := range input {
-		floatArray = append(floatArray, mesurement.MetricValue)
+// function to convert time slice to string slice that concat continuous date into period
+// dates are compared by calendar date in their own location so period is not broken by daylight saving time change
+func (a Application) DateArrayConcatString(times []time.Time) []string {
+	periods := a.findPeriods(times)
+	if periods == nil {
+		return nil
 	}
 
-	sort.Float64s(floatArray)
+	return a.periodStrings(periods)
+}
 
-	var median float64
-	var firstQuartile float64
-	var thirdQuartile float64
-	l := len(floatArray)
-	if l == 0 {
-		return 0, 0, 0
-	} else if l%2 == 0 {
-		median = (floatArray[l/2-1] + floatArray[l/2]) / 2
-		firstQuartile = (floatArray[l/4-1] + floatArray[l/4]) / 2
-		thirdQuartile = (floatArray[3*l/4-1] + floatArray[3*l/4]) / 2
-	} else {
-		median = floatArray[l/2]
-		firstQuartile = floatArray[l/4]
-		thirdQuartile = floatArray[3*l/4]
+// function to format periods as shown in report, period of one date is the date only
+func (a Application) periodStrings(periods []types.Period) []string {
+	result := make([]string, 0, len(periods))
+	for _, period := range periods {
+		if period.Days == 1 {
+			result = append(result, period.From.Format("2006-01-02"))
+		} else {
+			result = append(result, fmt.Sprintf("between %s and %s", period.From.Format("2006-01-02"), period.To.Format("2006-01-02")))
+		}
 	}
 
-	return median, firstQuartile, thirdQuartile - firstQuartile
+	return result
 }
 
-// function to find min date and max date from data set (order may not preserved in production)
-func (a Application) findMinMaxDate(input []types.Mesurement) (time.Time, time.Time) {
-	if len(input) == 0 {
-		return time.Time{}, time.Time{}
+// function to concat ascending times on continuous dates into periods, From and To of period are its first and last time
+func (a Application) findPeriods(times []time.Time) []types.Period {
+	if len(times) == 0 {
+		return nil
 	}
 
-	timeArray := make([]time.Time, 0, len(input))
+	result := make([]types.Period, 0)
+
+	period := types.Period{From: times[0], To: times[0], Days: 1}
+	for _, next := range times[1:] {
+		if sameDate(period.To, next) {
+			continue
+		}
+
+		if !sameDate(dayAfter(period.To), next) {
+			result = append(result, period)
+			period = types.Period{From: next, Days: 0}
+		}
 
-	for _, mesurement := range input {
-		timeArray = append(timeArray, mesurement.Dtime.Time)
+		period.To = next
+		period.Days++
 	}
+	result = append(result, period)
 
-	sort.Slice(timeArray, func(i, j int) bool {
-		return timeArray[i].Before(timeArray[j])
-	})
+	return result
+}
 
-	return timeArray[0], timeArray[len(timeArray)-1]
+// function to get calendar date after the date of time, computed in UTC since midnight of that date may not exist in location of time
+func dayAfter(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, time.UTC)
 }
 
-// function to find period that under performance (based on Q1 - 1.5 * IQR rules to find outlier)
-func (a Application) findUnderPerformance(input []types.Mesurement, firstQuartile float64, IQR float64) []time.Time {
-	result := make([]time.Time, 0)
+// function to check whether two time fall on the same calendar date (in their own location)
+func sameDate(a time.Time, b time.Time) bool {
+	aYear, aMonth, aDay := a.Date()
+	bYear, bMonth, bDay := b.Date()
 
-	for _, mesurement := range input {
-		if mesurement.MetricValue < firstQuartile-1.5*IQR {
-			result = append(result, mesurement.Dtime.Time)
-		}
-	}
-
-	return result
+	return aYear == bYear && aMonth == bMonth && aDay == bDay
 }
 
-// function to convert time slice to string slice that concat continuous date into period
-func (a Application) DateArrayConcatString(times []time.Time) []string {
-	if len(times) == 0 {
-		return nil
-	}
+// function to analyse one input into structured report
+func (a Application) Analyse(input types.InputFormat) types.Report {
+	location := a.locationOf(input.Name)
 
-	if len(times) == 1 {
-		return []string{times[0].Format("2006-01-02")}
+	groupings := make([]*grouping, 0, len(a.config.Aggregations)+1)
+	for _, aggregation := range a.config.Aggregations {
+		groupings = append(groupings, aggregation.grouping())
+	}
+	var window PeakWindow
+	if a.config.Peak != nil {
+		window = *a.config.Peak
+		if window.Location == nil {
+			window.Location = location
+		}
+		groupings = append(groupings, window.grouping())
 	}
 
-	result := make([]string, 0)
+	// single pass over the mesurements, every statistic of the report is derived from it
+	s := summarise(input.Content, location, groupings...)
+	statistics := s.total.statistics()
+	lowerFence := a.findLowerFence(&s.total)
+	underPerformancePeriod := a.findUnderPerformance(input.Content, s, lowerFence, location)
 
-	startCursor := times[0]
-	cursor := times[0]
-	for i := 1; i < len(times); i++ {
-		nextCursor := times[i]
+	unit, time := a.config.Unit.name, a.config.Unit.exponent
+	if a.config.Unit.name == "" {
+		unit, time = a.findOptimalUnit(a.findUnitValue(statistics))
+	}
 
-		fmt.Println("cursor", cursor)
-		fmt.Println("nextCursor", nextCursor)
-		fmt.Println("!cursor.AddDate(0, 0, 1).Equal(nextCursor)", !cursor.AddDate(0, 0, 1).Equal(nextCursor))
-		fmt.Println("i == len(times)-1", i == len(times)-1)
-		if i == len(times)-1 {
-			if startCursor.Equal(nextCursor) {
-				result = append(result, nextCursor.Format("2006-01-02"))
-			} else if !cursor.AddDate(0, 0, 1).Equal(nextCursor) {
-				result = append(result, fmt.Sprintf("between %s and %s", startCursor.Format("2006-01-02"), cursor.Format("2006-01-02")))
-				result = append(result, nextCursor.Format("2006-01-02"))
-			} else {
-				result = append(result, fmt.Sprintf("between %s and %s", startCursor.Format("2006-01-02"), nextCursor.Format("2006-01-02")))
-			}
-		} else if !cursor.AddDate(0, 0, 1).Equal(nextCursor) {
-			result = append(result, fmt.Sprintf("between %s and %s", startCursor.Format("2006-01-02"), cursor.Format("2006-01-02")))
-			startCursor = nextCursor
-		}
+	aggregations := make([]types.Aggregation, 0, len(a.config.Aggregations))
+	for i, aggregation := range a.config.Aggregations {
+		aggregations = append(aggregations, aggregation.result(groupings[i]))
+	}
 
-		cursor = nextCursor
+	var peak *types.PeakAnalysis
+	if a.config.Peak != nil {
+		result := window.result(groupings[len(groupings)-1])
+		peak = &result
 	}
 
-	return result
+	underPerformingDates := a.findUniqueDates(underPerformancePeriod)
+	underPerformingRanges := a.findPeriods(underPerformingDates)
+	if underPerformingRanges == nil {
+		underPerformingRanges = []types.Period{}
+	}
+	underPerformingPeriods := a.periodStrings(underPerformingRanges)
+
+	return types.Report{
+		Name:                   input.Name,
+		From:                   s.total.from,
+		To:                     s.total.to,
+		Unit:                   unit,
+		UnitExponent:           time,
+		Statistics:             statistics,
+		UnderPerformingPeriods: underPerformingPeriods,
+		UnderPerformingRanges:  underPerformingRanges,
+		UnderPerformingDays:    len(underPerformingDates),
+		Aggregations:           aggregations,
+		Peak:                   peak,
+	}
 }
 
 // function to run the pull data, process and report data
@@ -199,62 +325,125 @@ func (a Application) Run() error {
 		return err
 	}
 
-	for _, input := range inputArray {
-		min, max, mean := a.findMinMaxMean(input.Content)
-		median, firstQuartile, IQR := a.findMedianFirstQuartileIQR(input.Content)
-		underPerformancePeriod := a.findUnderPerformance(input.Content, firstQuartile, IQR)
-		minDate, maxDate := a.findMinMaxDate(input.Content)
+	return a.RunInputs(inputArray)
+}
 
-		minValue := math.Min(min, math.Min(max, math.Min(median, mean)))
-		unit, time := a.findOptimalUnit(minValue)
+// function to analyse and write reports of one input (e.g. new file found in watch mode)
+func (a Application) RunInput(name string) error {
+	input, err := a.reader.GetInput(name)
+	if err != nil {
+		return err
+	}
 
-		fileName := strings.Split(input.Name, ".")
-		var output string
-		if len(underPerformancePeriod) > 0 {
-			output = fmt.Sprintf(`SamKnows Metric Analyser v1.0.0
-===============================
+	return a.RunInputs([]types.InputFormat{input})
+}
 
-Period checked:
+// function to analyse and write reports of inputs already read (e.g. entries of archive found in watch mode)
+func (a Application) RunInputs(inputs []types.InputFormat) error {
+	// input without mesurement has no period or statistics to report, it is skipped with warning like servers reject empty series
+	analysed := make([]types.InputFormat, 0, len(inputs))
+	for _, input := range inputs {
+		if len(input.Content) == 0 {
+			log.Printf("skip %s: no mesurements to analyse", input.Name)
+			continue
+		}
+		analysed = append(analysed, input)
+	}
+	inputs = analysed
 
-    From: %s
-    To:   %s
+	// every name is planned before writing so collision fail without partial output
+	outputs, err := a.planOutputs(inputs, a.formats())
+	if err != nil {
+		return err
+	}
 
-Statistics:
+	// unchanged inputs are found before anything is written, so their own reports are not taken as conflict
+	keys := make([]string, len(inputs))
+	skipped := make([]bool, len(inputs))
+	for i, input := range inputs {
+		keys[i], skipped[i], err = a.unchanged(input, outputs[i])
+		if err != nil {
+			return err
+		}
+	}
 
-    Unit: %s
+	err = a.checkOverwrite(outputs, skipped)
+	if err != nil {
+		return err
+	}
 
-    Average: %.2f
-    Min: %.2f
-    Max: %.2f
-    Median: %.2f
+	for i, input := range inputs {
+		if skipped[i] {
+			continue
+		}
 
-Under-performing periods:
+		err := a.process(input, outputs[i], keys[i])
+		if err != nil {
+			return err
+		}
+	}
 
-    * The period %s
-      was under-performing.
-`, minDate.Format("2006-01-02"), maxDate.Format("2006-01-02"), unit, mean*8/math.Pow(1000, float64(time)), min*8/math.Pow(1000, float64(time)), max*8/math.Pow(1000, float64(time)), median*8/math.Pow(1000, float64(time)), strings.Join(a.DateArrayConcatString(underPerformancePeriod), ", "))
-		} else {
-			output = fmt.Sprintf(`SamKnows Metric Analyser v1.0.0
-===============================
+	if a.tracked != nil {
+		for i, input := range inputs {
+			a.tracked.store(input.Name, outputs[i])
+		}
+	}
 
-Period checked:
+	return nil
+}
 
-    From: %s
-    To:   %s
+// function to get configured report formats, text only when none configured
+func (a Application) formats() []Format {
+	if len(a.config.Formats) == 0 {
+		return []Format{FormatText}
+	}
+
+	return a.config.Formats
+}
+
+// function to analyse input and write report in every configured format to planned output name, cache key is stored once reports are written
+func (a Application) process(input types.InputFormat, outputs map[Format]string, key string) error {
+	report := a.Analyse(input)
+
+	rendered := make([]types.OutputFormat, 0, len(a.formats()))
+	for _, format := range a.formats() {
+		name := outputs[format]
+		if a.config.Naming.Overwrite == OverwriteSkip {
+			exists, err := a.outputExists(name)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+		}
 
-Statistics:
+		content, err := a.Render(report, format)
+		if err != nil {
+			return err
+		}
 
-    Unit: %s
+		rendered = append(rendered, types.OutputFormat{Name: name, Content: content})
+	}
 
-    Average: %.2f
-    Min: %.2f
-    Max: %.2f
-    Median: %.2f
-`, minDate.Format("2006-01-02"), maxDate.Format("2006-01-02"), unit, mean*8/math.Pow(1000, float64(time)), min*8/math.Pow(1000, float64(time)), max*8/math.Pow(1000, float64(time)), median*8/math.Pow(1000, float64(time)))
+	// every format of the input is replaced together, so failure never leave reports of different runs side by side
+	if len(rendered) > 0 {
+		err := a.writer.WriteMultipleOutput(rendered)
+		if err != nil {
+			return err
 		}
+	}
 
-		a.writer.WriteOutput(fileName[0]+".output", []byte(output))
+	// writer that store structured result (e.g. database) get the report as well
+	if reportWriter, ok := a.writer.(writer.ReportWriter); ok {
+		err := reportWriter.WriteReport(strings.TrimSuffix(input.Name, path.Ext(input.Name)), report)
+		if err != nil {
+			return err
+		}
 	}
 
+	if a.cache != nil {
+		return a.cache.Store(input.Name, key)
+	}
 	return nil
 }
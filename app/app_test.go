@@ -1,9 +1,13 @@
 package app
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
 	"github.com/awcjack/samknows-backend-code-test/types"
 )
 
@@ -69,7 +73,77 @@ func TestFindOptimalUnit(t *testing.T) {
 	}
 }
 
-func TestFindMinMaxMean(t *testing.T) {
+func TestFindUnitValue(t *testing.T) {
+	type testcase struct {
+		name       string
+		input      types.Statistics
+		result     float64
+		unit       string
+		resultTime int
+	}
+
+	testcases := []testcase{
+		{
+			name:       "No outage",
+			input:      types.Statistics{Min: 500000, Max: 1500000, Mean: 1000000, Median: 1000000},
+			result:     500000,
+			unit:       "Megabits per second",
+			resultTime: 2,
+		},
+		{
+			name:       "Outage",
+			input:      types.Statistics{Min: 0, Max: 1500000, Mean: 1000000, Median: 1250000},
+			result:     1000000,
+			unit:       "Megabits per second",
+			resultTime: 2,
+		},
+		{
+			name:       "Outage only",
+			input:      types.Statistics{},
+			result:     0,
+			unit:       "Bits per second",
+			resultTime: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			value := app.findUnitValue(tc.input)
+			if value != tc.result {
+				t.Errorf("Expected get %v, but got %v", tc.result, value)
+			}
+			unit, time := app.findOptimalUnit(value)
+			if unit != tc.unit {
+				t.Errorf("Expected get %s, but got %s", tc.unit, unit)
+			}
+			if time != tc.resultTime {
+				t.Errorf("Expected get %d, but got %d", tc.resultTime, time)
+			}
+		})
+	}
+}
+
+func TestAnalyseOutage(t *testing.T) {
+	input := types.InputFormat{
+		Name: "outage.json",
+		Content: []types.Mesurement{
+			{MetricValue: 1000000, Dtime: types.JSONTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}},
+			{MetricValue: 0, Dtime: types.JSONTime{Time: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)}},
+			{MetricValue: 1000000, Dtime: types.JSONTime{Time: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC)}},
+		},
+	}
+
+	// outage is the min, while report keep the unit of the other values
+	report := app.Analyse(input)
+	if report.Statistics.Min != 0 {
+		t.Errorf("Expected get %v, but got %v", 0, report.Statistics.Min)
+	}
+	if report.Unit != "Megabits per second" {
+		t.Errorf("Expected get %v, but got %v", "Megabits per second", report.Unit)
+	}
+}
+
+func TestSummariseMinMaxMean(t *testing.T) {
 	type testcase struct {
 		name  string
 		input []types.Mesurement
@@ -123,6 +197,26 @@ func TestFindMinMaxMean(t *testing.T) {
 			max:  3,
 			mean: 2,
 		},
+		{
+			name: "Outage",
+			input: []types.Mesurement{
+				{
+					MetricValue: 2,
+					Dtime:       types.JSONTime{Time: time.Time{}},
+				},
+				{
+					MetricValue: 0,
+					Dtime:       types.JSONTime{Time: time.Time{}},
+				},
+				{
+					MetricValue: 4,
+					Dtime:       types.JSONTime{Time: time.Time{}},
+				},
+			},
+			min:  0,
+			max:  4,
+			mean: 2,
+		},
 
 		{
 			name:  "Empty",
@@ -135,7 +229,8 @@ func TestFindMinMaxMean(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			min, max, mean := app.findMinMaxMean(tc.input)
+			s := summarise(tc.input, nil)
+			min, max, mean := s.total.min, s.total.max, s.total.mean()
 			if min != tc.min {
 				t.Errorf("Expected get %v, but got %v", tc.min, min)
 			}
@@ -149,7 +244,7 @@ func TestFindMinMaxMean(t *testing.T) {
 	}
 }
 
-func TestFindMedianFirstQuartileIQR(t *testing.T) {
+func TestSummariseQuartiles(t *testing.T) {
 	type testcase struct {
 		name          string
 		input         []types.Mesurement
@@ -203,6 +298,22 @@ func TestFindMedianFirstQuartileIQR(t *testing.T) {
 			firstQuartile: 1,
 			iqr:           2,
 		},
+		{
+			name: "Two element",
+			input: []types.Mesurement{
+				{
+					MetricValue: 1,
+					Dtime:       types.JSONTime{Time: time.Time{}},
+				},
+				{
+					MetricValue: 3,
+					Dtime:       types.JSONTime{Time: time.Time{}},
+				},
+			},
+			median:        2,
+			firstQuartile: 1,
+			iqr:           2,
+		},
 		{
 			name:          "Empty",
 			input:         []types.Mesurement{},
@@ -214,7 +325,8 @@ func TestFindMedianFirstQuartileIQR(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			median, firstQuartile, iqr := app.findMedianFirstQuartileIQR(tc.input)
+			s := summarise(tc.input, nil)
+			median, firstQuartile, iqr := s.total.median, s.total.firstQuartile, s.total.IQR
 			if median != tc.median {
 				t.Errorf("Expected get %v, but got %v", tc.median, median)
 			}
@@ -228,7 +340,7 @@ func TestFindMedianFirstQuartileIQR(t *testing.T) {
 	}
 }
 
-func TestFindMinMaxDate(t *testing.T) {
+func TestSummariseDateRange(t *testing.T) {
 	type testcase struct {
 		name    string
 		input   []types.Mesurement
@@ -269,7 +381,8 @@ func TestFindMinMaxDate(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			minDate, maxDate := app.findMinMaxDate(tc.input)
+			s := summarise(tc.input, nil)
+			minDate, maxDate := s.total.from, s.total.to
 			if !minDate.Equal(tc.minDate) {
 				t.Errorf("Expected get %v, but got %v", tc.minDate, minDate)
 			}
@@ -360,7 +473,7 @@ func TestFindUnderPerformance(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			times := app.findUnderPerformance(tc.input, tc.firstQuartile, tc.iqr)
+			times := app.findUnderPerformance(tc.input, summarise(tc.input, nil), tc.firstQuartile-1.5*tc.iqr, nil)
 			if len(times) != len(tc.result) {
 				t.Errorf("Expected get %v, but got %v", tc.result, times)
 			}
@@ -373,6 +486,48 @@ func TestFindUnderPerformance(t *testing.T) {
 	}
 }
 
+func TestFindUniqueDates(t *testing.T) {
+	type testcase struct {
+		name   string
+		input  []time.Time
+		result []time.Time
+	}
+
+	day1, _ := time.Parse("2006-01-02", "2006-01-01")
+	day2, _ := time.Parse("2006-01-02", "2006-01-02")
+	testcases := []testcase{
+		{
+			name: "Time of day",
+			input: []time.Time{
+				day2.Add(21 * time.Hour),
+				day1.Add(20 * time.Hour),
+				day2.Add(20 * time.Hour),
+				day1,
+			},
+			result: []time.Time{day1, day2},
+		},
+		{
+			name:   "Empty",
+			input:  []time.Time{},
+			result: []time.Time{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dates := app.findUniqueDates(tc.input)
+			if len(dates) != len(tc.result) {
+				t.Fatalf("Expected get %v, but got %v", tc.result, dates)
+			}
+			for i, date := range dates {
+				if !date.Equal(tc.result[i]) {
+					t.Errorf("Expected get %v, but got %v", tc.result, dates)
+				}
+			}
+		})
+	}
+}
+
 func TestDateArrayConcatString(t *testing.T) {
 	type testcase struct {
 		name   string
@@ -439,6 +594,159 @@ func TestDateArrayConcatString(t *testing.T) {
 	}
 }
 
+func TestFindPeriods(t *testing.T) {
+	type testcase struct {
+		name   string
+		input  []time.Time
+		result []types.Period
+	}
+
+	day := func(day int) time.Time {
+		return time.Date(2006, 1, day, 0, 0, 0, 0, time.UTC)
+	}
+	testcases := []testcase{
+		{
+			name:   "Continuous",
+			input:  []time.Time{day(1), day(2), day(3)},
+			result: []types.Period{{From: day(1), To: day(3), Days: 3}},
+		},
+		{
+			name:   "Gap",
+			input:  []time.Time{day(1), day(2), day(5)},
+			result: []types.Period{{From: day(1), To: day(2), Days: 2}, {From: day(5), To: day(5), Days: 1}},
+		},
+		{
+			name:   "Same date",
+			input:  []time.Time{day(1), day(1).Add(time.Hour), day(2)},
+			result: []types.Period{{From: day(1), To: day(2), Days: 2}},
+		},
+		{
+			name:   "Empty",
+			input:  []time.Time{},
+			result: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			periods := app.findPeriods(tc.input)
+			if len(periods) != len(tc.result) {
+				t.Fatalf("Expected get %v, but got %v", tc.result, periods)
+			}
+			for i, period := range periods {
+				if !period.From.Equal(tc.result[i].From) || !period.To.Equal(tc.result[i].To) || period.Days != tc.result[i].Days {
+					t.Errorf("Expected get %v, but got %v", tc.result[i], period)
+				}
+			}
+		})
+	}
+}
+
+// function to parse period strings back into inclusive ranges of day number
+func parsePeriods(t *testing.T, periods []string) [][2]int64 {
+	dayNumber := func(value string) int64 {
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			t.Fatalf("Expected date, but got %q", value)
+		}
+		return date.Unix() / 86400
+	}
+
+	result := make([][2]int64, 0, len(periods))
+	for _, period := range periods {
+		var from, to string
+		if _, err := fmt.Sscanf(period, "between %s and %s", &from, &to); err == nil {
+			result = append(result, [2]int64{dayNumber(from), dayNumber(to)})
+		} else {
+			day := dayNumber(period)
+			result = append(result, [2]int64{day, day})
+		}
+	}
+
+	return result
+}
+
+func FuzzDateArrayConcatString(f *testing.F) {
+	base := time.Date(2011, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// seed with dates of every real input file
+	files, err := filepath.Glob("testdata/corpus/input/*.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		mesurements, err := reader.DecodeJSON(content)
+		if err != nil || len(mesurements) == 0 {
+			continue
+		}
+		first := mesurements[0].Dtime.Time
+		offsets := make([]byte, 0, len(mesurements))
+		for _, mesurement := range mesurements {
+			offsets = append(offsets, byte(mesurement.Dtime.Sub(first).Hours()/24))
+		}
+		f.Add(uint16(first.Sub(base).Hours()/24), offsets, uint8(0))
+	}
+	f.Add(uint16(0), []byte{}, uint8(0))
+	// around daylight saving time change of London and Sao Paulo
+	f.Add(uint16(86), []byte{0, 1, 2, 4, 5, 5, 9}, uint8(1))
+	f.Add(uint16(2845), []byte{0, 1, 3, 4}, uint8(2))
+
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		f.Skip(err)
+	}
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		f.Skip(err)
+	}
+	locations := []*time.Location{time.UTC, london, saoPaulo}
+
+	f.Fuzz(func(t *testing.T, start uint16, offsets []byte, zone uint8) {
+		location := locations[int(zone)%len(locations)]
+		first := base.AddDate(0, 0, int(start)%8000)
+
+		times := make([]time.Time, 0, len(offsets))
+		expected := make(map[int64]bool)
+		for i, offset := range offsets {
+			date := first.AddDate(0, 0, int(offset))
+			// hour skipped by daylight saving time change is normalised, so expected date is taken from the time itself
+			local := time.Date(date.Year(), date.Month(), date.Day(), (i*7)%24, 0, 0, 0, location)
+			times = append(times, local)
+			expected[time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC).Unix()/86400] = true
+		}
+
+		periods := parsePeriods(t, app.DateArrayConcatString(app.findUniqueDates(times)))
+
+		// ranges are ordered, non-overlapping and separated by at least one missing date (otherwise they would be one range)
+		for i, period := range periods {
+			if period[0] > period[1] {
+				t.Errorf("Expected ordered range, but got %v", period)
+			}
+			if i > 0 && period[0] <= periods[i-1][1]+1 {
+				t.Errorf("Expected separated range, but got %v after %v", period, periods[i-1])
+			}
+		}
+
+		// every input date is covered exactly once and nothing else is covered
+		covered := make(map[int64]bool)
+		for _, period := range periods {
+			for day := period[0]; day <= period[1]; day++ {
+				if covered[day] || !expected[day] {
+					t.Errorf("Expected get dates %v, but got periods %v", expected, periods)
+				}
+				covered[day] = true
+			}
+		}
+		if len(covered) != len(expected) {
+			t.Errorf("Expected get dates %v, but got periods %v", expected, periods)
+		}
+	})
+}
+
 type mockReader struct{}
 
 func (r mockReader) GetInputs() ([]types.InputFormat, error) {
@@ -0,0 +1,171 @@
+package app
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// series sizes benchmarked, 10M is skipped with -short
+var benchmarkSizes = []int{1000, 100000, 10000000}
+
+var syntheticCache = make(map[int][]types.Mesurement)
+
+// function to make series of n mesurements spread over one year with daily pattern and outages, generated once per size
+func syntheticSeries(n int) []types.Mesurement {
+	if series, ok := syntheticCache[n]; ok {
+		return series
+	}
+
+	r := rand.New(rand.NewSource(int64(n)))
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	step := 365 * 24 * time.Hour / time.Duration(n)
+
+	series := make([]types.Mesurement, n)
+	for i := range series {
+		value := 1.2e7 + r.NormFloat64()*1e6
+		if r.Intn(100) == 0 {
+			value = r.Float64() * 1e5
+		}
+		series[i] = types.Mesurement{
+			MetricValue: value,
+			Dtime:       types.JSONTime{Time: start.Add(time.Duration(i) * step)},
+		}
+	}
+	// production input is not guaranteed to be ordered
+	r.Shuffle(len(series), func(i, j int) {
+		series[i], series[j] = series[j], series[i]
+	})
+
+	syntheticCache[n] = series
+	return series
+}
+
+// function to run benchmark body for every series size
+func benchmarkSizesOf(b *testing.B, body func(b *testing.B, input []types.Mesurement)) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			if size > 1000000 && testing.Short() {
+				b.Skip("skipping large series in short mode")
+			}
+			input := syntheticSeries(size)
+			b.ReportAllocs()
+			b.ResetTimer()
+			body(b, input)
+		})
+	}
+}
+
+// statistics, quartiles and date range of the whole series in one pass
+func BenchmarkSummarise(b *testing.B) {
+	benchmarkSizesOf(b, func(b *testing.B, input []types.Mesurement) {
+		for i := 0; i < b.N; i++ {
+			summarise(input, time.UTC)
+		}
+	})
+}
+
+// the same pass also split into weekly, monthly and peak groups
+func BenchmarkSummariseGrouped(b *testing.B) {
+	window := PeakWindow{Start: 20 * 60, End: 22 * 60, Location: time.UTC, Threshold: 0.8}
+	benchmarkSizesOf(b, func(b *testing.B, input []types.Mesurement) {
+		for i := 0; i < b.N; i++ {
+			summarise(input, time.UTC, AggregationWeek.grouping(), AggregationMonth.grouping(), window.grouping())
+		}
+	})
+}
+
+func BenchmarkFindUnderPerformance(b *testing.B) {
+	benchmarkSizesOf(b, func(b *testing.B, input []types.Mesurement) {
+		s := summarise(input, nil)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			app.findUnderPerformance(input, s, 1e6, time.UTC)
+		}
+	})
+}
+
+// period merging of every under-performing timestamp (the worst case, every mesurement under-performing)
+func BenchmarkDateArrayConcatString(b *testing.B) {
+	benchmarkSizesOf(b, func(b *testing.B, input []types.Mesurement) {
+		times := app.findUnderPerformance(input, summarise(input, nil), 1e9, nil)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			app.DateArrayConcatString(app.findUniqueDates(times))
+		}
+	})
+}
+
+func BenchmarkAnalyse(b *testing.B) {
+	benchmarkSizesOf(b, func(b *testing.B, input []types.Mesurement) {
+		for i := 0; i < b.N; i++ {
+			app.Analyse(types.InputFormat{Name: "device.json", Content: input})
+		}
+	})
+}
+
+// allocations allowed for analysing one input with every aggregation and peak hours enabled, whatever the number of mesurements
+// Analyse allocates per bucket and per under-performing date, never per mesurement (about 400 for 1k and 1050 for 1M points when it was set)
+const analyseAllocationBudget = 1500
+
+// performance budget of Analyse, allocation count is checked instead of time so it is not flaky on slower machine
+func TestAnalyseAllocationBudget(t *testing.T) {
+	app := NewApplicationWithConfig(mockReader{}, mockWriter{}, Config{
+		Aggregations: []Aggregation{AggregationWeek, AggregationMonth, AggregationWeekday},
+		Peak:         &PeakWindow{Start: 20 * 60, End: 22 * 60, Location: time.UTC, Threshold: 0.8},
+	})
+
+	for _, size := range []int{1000, 100000, 1000000} {
+		t.Run(fmt.Sprintf("%d", size), func(t *testing.T) {
+			if size > 100000 && testing.Short() {
+				t.Skip("skipping large series in short mode")
+			}
+			input := types.InputFormat{Name: "device.json", Content: syntheticSeries(size)}
+
+			allocs := testing.AllocsPerRun(3, func() {
+				app.Analyse(input)
+			})
+			if allocs > analyseAllocationBudget {
+				t.Errorf("Expected get at most %v allocations, but got %v", analyseAllocationBudget, allocs)
+			}
+		})
+	}
+}
+
+// report formatting in every format of one analysed input
+func BenchmarkRender(b *testing.B) {
+	report := app.Analyse(types.InputFormat{Name: "device.json", Content: syntheticSeries(1000)})
+	for _, format := range Formats {
+		b.Run(string(format), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := app.Render(report, format)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRun(b *testing.B) {
+	benchmarkSizesOf(b, func(b *testing.B, input []types.Mesurement) {
+		reader := mockInputsReader{inputs: []types.InputFormat{{Name: "device.json", Content: input}}}
+		app := NewApplicationWithConfig(reader, writer.NewMemoryWriter(), Config{
+			Formats:      Formats,
+			Aggregations: []Aggregation{AggregationWeek, AggregationMonth},
+			Peak:         &PeakWindow{Start: 20 * 60, End: 22 * 60, Threshold: 0.8},
+		})
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			err := app.Run()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
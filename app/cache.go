@@ -0,0 +1,94 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/cache"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// bump when analysis or rendering change, so reports cached by older version are rewritten
+const cacheVersion = "1"
+
+// function to make application skip inputs whose content and analysis options are unchanged since last run, force rewrite every report but still update the cache
+func (a Application) WithCache(c cache.Cache, force bool) Application {
+	a.cache = c
+	a.force = force
+	return a
+}
+
+// function to check whether input is unchanged since last run and every planned output of it is still written, so it can be skipped
+// cache key is returned to be stored once reports are written, it is empty when no cache is provided
+func (a Application) unchanged(input types.InputFormat, outputs map[Format]string) (string, bool, error) {
+	if a.cache == nil {
+		return "", false, nil
+	}
+
+	key := a.cacheKey(input)
+	if a.force || !a.cache.Hit(input.Name, key) {
+		return key, false, nil
+	}
+
+	// output deleted (or moved to other location) since last run is written again
+	written, err := a.outputsWritten(outputs)
+	if err != nil {
+		return "", false, err
+	}
+
+	return key, written, nil
+}
+
+// function to check whether every planned output of the input still exist, writer that cannot tell is trusted
+func (a Application) outputsWritten(outputs map[Format]string) (bool, error) {
+	checker, ok := a.writer.(writer.ExistenceChecker)
+	if !ok {
+		return true, nil
+	}
+
+	for _, format := range a.formats() {
+		exists, err := checker.Exists(outputs[format])
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// function to derive cache key of input from its mesurements and every option that affect its reports
+func (a Application) cacheKey(input types.InputFormat) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "version=%s\n", cacheVersion)
+	fmt.Fprintf(&sb, "name=%s\n", input.Name)
+	// formats actually rendered, so template of default text format is part of the key as well
+	fmt.Fprintf(&sb, "formats=%v\n", a.formats())
+	for _, format := range a.formats() {
+		if tmpl, ok := a.config.Templates[format]; ok {
+			fmt.Fprintf(&sb, "template.%s=%s\n", format, cache.Key([]byte(tmpl.source)))
+		}
+	}
+	fmt.Fprintf(&sb, "naming=%s %s\n", a.config.Naming.source, a.config.Naming.Collision)
+	fmt.Fprintf(&sb, "aggregations=%v\n", a.config.Aggregations)
+	fmt.Fprintf(&sb, "location=%s\n", a.locationOf(input.Name))
+	if peak := a.config.Peak; peak != nil {
+		location := "input"
+		if peak.Location != nil {
+			location = peak.Location.String()
+		}
+		fmt.Fprintf(&sb, "peak=%d-%d %s %v\n", peak.Start, peak.End, location, peak.Threshold)
+	}
+	fmt.Fprintf(&sb, "outlier=%s %v\n", a.config.Outlier.Method, a.config.Outlier.Multiplier)
+	fmt.Fprintf(&sb, "unit=%s %d\n", a.config.Unit.name, a.config.Unit.exponent)
+
+	for _, mesurement := range input.Content {
+		fmt.Fprintf(&sb, "%v %d %t\n", mesurement.MetricValue, mesurement.Dtime.UnixNano(), mesurement.Dtime.WallClock)
+	}
+
+	return cache.Key([]byte(sb.String()))
+}
@@ -0,0 +1,246 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+type mockInputsReader struct {
+	inputs []types.InputFormat
+}
+
+func (r mockInputsReader) GetInputs() ([]types.InputFormat, error) {
+	return r.inputs, nil
+}
+
+func (r mockInputsReader) GetInput(name string) (types.InputFormat, error) {
+	return types.InputFormat{}, nil
+}
+
+// writer that remember name of written outputs
+type mockRecordingWriter struct {
+	written *[]string
+}
+
+func (w mockRecordingWriter) WriteMultipleOutput(outputs []types.OutputFormat) error {
+	for _, output := range outputs {
+		*w.written = append(*w.written, output.Name)
+	}
+	return nil
+}
+
+func (w mockRecordingWriter) WriteOutput(name string, content []byte) error {
+	*w.written = append(*w.written, name)
+	return nil
+}
+
+type mockCache map[string]string
+
+func (c mockCache) Hit(name string, key string) bool {
+	return c[name] == key
+}
+
+func (c mockCache) Store(name string, key string) error {
+	c[name] = key
+	return nil
+}
+
+func TestRunCache(t *testing.T) {
+	series := func(last float64) types.InputFormat {
+		content := make([]types.Mesurement, 0)
+		for i := 0; i < 5; i++ {
+			value := 1000000.0
+			if i == 4 {
+				value = last
+			}
+			content = append(content, types.Mesurement{
+				MetricValue: value,
+				Dtime:       types.JSONTime{Time: time.Date(2021, 1, 1+i, 0, 0, 0, 0, time.UTC), WallClock: true},
+			})
+		}
+		return types.InputFormat{Name: "1.json", Content: content}
+	}
+
+	tmpl, err := ParseTemplate(FormatText, "{{.Name}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testcase struct {
+		name     string
+		input    types.InputFormat
+		config   Config
+		force    bool
+		expected int
+	}
+
+	// cases run in order against the same cache
+	testcases := []testcase{
+		{
+			name:     "First run",
+			input:    series(10),
+			expected: 1,
+		},
+		{
+			name:     "Unchanged",
+			input:    series(10),
+			expected: 0,
+		},
+		{
+			name:     "Input changed",
+			input:    series(20),
+			expected: 1,
+		},
+		{
+			name:     "Default format template changed",
+			input:    series(20),
+			config:   Config{Templates: map[Format]Template{FormatText: tmpl}},
+			expected: 1,
+		},
+		{
+			name:     "Option changed",
+			input:    series(20),
+			config:   Config{Outlier: Outlier{Method: OutlierZScore}},
+			expected: 1,
+		},
+		{
+			name:     "Format added",
+			input:    series(20),
+			config:   Config{Outlier: Outlier{Method: OutlierZScore}, Formats: []Format{FormatText, FormatJSON}},
+			expected: 2,
+		},
+		{
+			name:     "Force",
+			input:    series(20),
+			config:   Config{Outlier: Outlier{Method: OutlierZScore}, Formats: []Format{FormatText, FormatJSON}},
+			force:    true,
+			expected: 2,
+		},
+	}
+
+	c := mockCache{}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			written := make([]string, 0)
+			application := NewApplicationWithConfig(mockInputsReader{inputs: []types.InputFormat{tc.input}}, mockRecordingWriter{written: &written}, tc.config).WithCache(c, tc.force)
+
+			err := application.Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(written) != tc.expected {
+				t.Errorf("Expected get %d, but got %d (%v)", tc.expected, len(written), written)
+			}
+		})
+	}
+}
+
+func TestRunCacheOutputDeleted(t *testing.T) {
+	type testcase struct {
+		name     string
+		existing map[string]bool
+		expected int
+	}
+
+	// cases run in order against the same cache
+	testcases := []testcase{
+		{
+			name:     "First run",
+			existing: map[string]bool{},
+			expected: 1,
+		},
+		{
+			name:     "Output kept",
+			existing: map[string]bool{"1.output": true},
+			expected: 0,
+		},
+		{
+			name:     "Output deleted",
+			existing: map[string]bool{},
+			expected: 1,
+		},
+	}
+
+	c := mockCache{}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			written := make([]string, 0)
+			writer := mockExistingWriter{mockRecordingWriter{&written}, tc.existing}
+			application := NewApplicationWithConfig(mockInputsReader{inputs: []types.InputFormat{namingInput("1.json")}}, writer, Config{}).WithCache(c, false)
+
+			err := application.Run()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(written) != tc.expected {
+				t.Errorf("Expected get %d, but got %d (%v)", tc.expected, len(written), written)
+			}
+		})
+	}
+}
+
+func TestRunCacheOverwriteFail(t *testing.T) {
+	naming, err := NewNaming(DefaultNameTemplate, CollisionError, OverwriteFail)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testcase struct {
+		name     string
+		input    types.InputFormat
+		existing map[string]bool
+		expected int
+		err      string
+	}
+
+	changed := namingInput("1.json")
+	changed.Content[0].MetricValue = 2000000
+
+	// cases run in order against the same cache
+	testcases := []testcase{
+		{
+			name:     "First run",
+			input:    namingInput("1.json"),
+			existing: map[string]bool{},
+			expected: 1,
+		},
+		{
+			name:     "Unchanged",
+			input:    namingInput("1.json"),
+			existing: map[string]bool{"1.output": true},
+			expected: 0,
+		},
+		{
+			name:     "Input changed",
+			input:    changed,
+			existing: map[string]bool{"1.output": true},
+			err:      "output 1.output already exists",
+		},
+	}
+
+	c := mockCache{}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			written := make([]string, 0)
+			writer := mockExistingWriter{mockRecordingWriter{&written}, tc.existing}
+			application := NewApplicationWithConfig(mockInputsReader{inputs: []types.InputFormat{tc.input}}, writer, Config{Naming: naming}).WithCache(c, false)
+
+			err := application.Run()
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Expected get %v, but got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(written) != tc.expected {
+				t.Errorf("Expected get %d, but got %d (%v)", tc.expected, len(written), written)
+			}
+		})
+	}
+}
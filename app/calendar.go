@@ -0,0 +1,92 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// calendar unit that mesurement can be resampled into
+type Aggregation string
+
+const (
+	AggregationWeek    Aggregation = "week"
+	AggregationMonth   Aggregation = "month"
+	AggregationWeekday Aggregation = "weekday"
+)
+
+// function to parse aggregation name provided by user
+func ParseAggregation(name string) (Aggregation, error) {
+	switch Aggregation(name) {
+	case AggregationWeek, AggregationMonth, AggregationWeekday:
+		return Aggregation(name), nil
+	}
+
+	return "", fmt.Errorf("unknown aggregation %q (expected week, month or weekday)", name)
+}
+
+// title used when rendering the aggregation
+func (g Aggregation) Title() string {
+	switch g {
+	case AggregationWeek:
+		return "Weekly statistics"
+	case AggregationMonth:
+		return "Monthly statistics"
+	case AggregationWeekday:
+		return "Day of week statistics"
+	}
+
+	return string(g)
+}
+
+// function to find the order of the bucket that the time belong to, order is unique per bucket so it is also the key of the bucket
+func (g Aggregation) bucketOf(t time.Time) int {
+	switch g {
+	case AggregationWeek:
+		year, week := t.ISOWeek()
+		return year*100 + week
+	case AggregationMonth:
+		year, month, _ := t.Date()
+		return year*100 + int(month)
+	default:
+		// Monday first
+		return (int(t.Weekday()) + 6) % 7
+	}
+}
+
+// function to find the label of the bucket that the time belong to (only formatted once per bucket)
+func (g Aggregation) labelOf(t time.Time) string {
+	switch g {
+	case AggregationWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case AggregationMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Weekday().String()
+	}
+}
+
+// function to make grouping that resample mesurements into calendar buckets
+func (g Aggregation) grouping() *grouping {
+	return newGrouping(g.bucketOf, g.labelOf)
+}
+
+// function to build statistics of every bucket in calendar order from the summarised grouping
+// under-performance is judged by the lower fence of the whole dataset so the counts in each bucket are comparable
+func (g Aggregation) result(buckets *grouping) types.Aggregation {
+	result := make([]types.Bucket, 0, len(buckets.groups))
+	for _, bucket := range buckets.ordered() {
+		result = append(result, types.Bucket{
+			Label:                bucket.label,
+			Statistics:           bucket.summary.statistics(),
+			UnderPerformingCount: bucket.summary.underPerforming,
+		})
+	}
+
+	return types.Aggregation{
+		Name:    g.Title(),
+		Buckets: result,
+	}
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestParseAggregation(t *testing.T) {
+	type testcase struct {
+		name   string
+		input  string
+		result Aggregation
+		err    bool
+	}
+
+	testcases := []testcase{
+		{
+			name:   "Week",
+			input:  "week",
+			result: AggregationWeek,
+		},
+		{
+			name:   "Month",
+			input:  "month",
+			result: AggregationMonth,
+		},
+		{
+			name:   "Weekday",
+			input:  "weekday",
+			result: AggregationWeekday,
+		},
+		{
+			name:  "Unknown",
+			input: "year",
+			err:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			aggregation, err := ParseAggregation(tc.input)
+			if (err != nil) != tc.err {
+				t.Errorf("Expected error %v, but got %v", tc.err, err)
+			}
+			if aggregation != tc.result {
+				t.Errorf("Expected get %v, but got %v", tc.result, aggregation)
+			}
+		})
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	type testcase struct {
+		name        string
+		input       []types.Mesurement
+		aggregation Aggregation
+		lowerFence  float64
+		result      []types.Bucket
+	}
+
+	// 2021-01-31 is Sunday of ISO week 2021-W04, 2021-02-01 is Monday of 2021-W05
+	day1, _ := time.Parse("2006-01-02", "2021-01-30")
+	day2, _ := time.Parse("2006-01-02", "2021-01-31")
+	day3, _ := time.Parse("2006-01-02", "2021-02-01")
+	day4, _ := time.Parse("2006-01-02", "2021-02-08")
+	input := []types.Mesurement{
+		{
+			MetricValue: 4,
+			Dtime:       types.JSONTime{Time: day3},
+		},
+		{
+			MetricValue: 1,
+			Dtime:       types.JSONTime{Time: day1},
+		},
+		{
+			MetricValue: 3,
+			Dtime:       types.JSONTime{Time: day2},
+		},
+		{
+			MetricValue: 8,
+			Dtime:       types.JSONTime{Time: day4},
+		},
+	}
+	testcases := []testcase{
+		{
+			name:        "Month",
+			input:       input,
+			aggregation: AggregationMonth,
+			lowerFence:  2,
+			result: []types.Bucket{
+				{
+					Label:                "2021-01",
+					Statistics:           types.Statistics{Count: 2, Min: 1, Max: 3, Mean: 2, Median: 2, FirstQuartile: 1, IQR: 2},
+					UnderPerformingCount: 1,
+				},
+				{
+					Label:                "2021-02",
+					Statistics:           types.Statistics{Count: 2, Min: 4, Max: 8, Mean: 6, Median: 6, FirstQuartile: 4, IQR: 4},
+					UnderPerformingCount: 0,
+				},
+			},
+		},
+		{
+			name:        "Week",
+			input:       input,
+			aggregation: AggregationWeek,
+			lowerFence:  2,
+			result: []types.Bucket{
+				{
+					Label:                "2021-W04",
+					Statistics:           types.Statistics{Count: 2, Min: 1, Max: 3, Mean: 2, Median: 2, FirstQuartile: 1, IQR: 2},
+					UnderPerformingCount: 1,
+				},
+				{
+					Label:                "2021-W05",
+					Statistics:           types.Statistics{Count: 1, Min: 4, Max: 4, Mean: 4, Median: 4, FirstQuartile: 4, IQR: 0},
+					UnderPerformingCount: 0,
+				},
+				{
+					Label:                "2021-W06",
+					Statistics:           types.Statistics{Count: 1, Min: 8, Max: 8, Mean: 8, Median: 8, FirstQuartile: 8, IQR: 0},
+					UnderPerformingCount: 0,
+				},
+			},
+		},
+		{
+			name:        "Weekday",
+			input:       input,
+			aggregation: AggregationWeekday,
+			lowerFence:  2,
+			result: []types.Bucket{
+				{
+					Label:                "Monday",
+					Statistics:           types.Statistics{Count: 2, Min: 4, Max: 8, Mean: 6, Median: 6, FirstQuartile: 4, IQR: 4},
+					UnderPerformingCount: 0,
+				},
+				{
+					Label:                "Saturday",
+					Statistics:           types.Statistics{Count: 1, Min: 1, Max: 1, Mean: 1, Median: 1, FirstQuartile: 1, IQR: 0},
+					UnderPerformingCount: 1,
+				},
+				{
+					Label:                "Sunday",
+					Statistics:           types.Statistics{Count: 1, Min: 3, Max: 3, Mean: 3, Median: 3, FirstQuartile: 3, IQR: 0},
+					UnderPerformingCount: 0,
+				},
+			},
+		},
+		{
+			name:        "Empty",
+			input:       []types.Mesurement{},
+			aggregation: AggregationMonth,
+			result:      []types.Bucket{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			buckets := tc.aggregation.grouping()
+			summarise(tc.input, nil, buckets).below(tc.lowerFence)
+			aggregation := tc.aggregation.result(buckets)
+			if aggregation.Name != tc.aggregation.Title() {
+				t.Errorf("Expected get %v, but got %v", tc.aggregation.Title(), aggregation.Name)
+			}
+			if len(aggregation.Buckets) != len(tc.result) {
+				t.Fatalf("Expected get %v, but got %v", tc.result, aggregation.Buckets)
+			}
+			for i, bucket := range aggregation.Buckets {
+				if bucket != tc.result[i] {
+					t.Errorf("Expected get %v, but got %v", tc.result[i], bucket)
+				}
+			}
+		})
+	}
+}
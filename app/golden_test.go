@@ -0,0 +1,124 @@
+package app
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
+)
+
+// run `go test ./app -update` after intended change of report to regenerate golden files, then review the diff
+var update = flag.Bool("update", false, "rewrite golden files in testdata with current reports")
+
+// outputs kept by in-memory writer
+type outputs interface {
+	Names() []string
+	Output(name string) ([]byte, bool)
+}
+
+// function to compare every output with golden file of the same name under dir (and that there is no missing or extra output), golden files are rewritten instead with -update
+func assertGolden(t *testing.T, dir string, w outputs) {
+	t.Helper()
+
+	if *update {
+		err := os.RemoveAll(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, name := range w.Names() {
+			content, _ := w.Output(name)
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			err := os.MkdirAll(filepath.Dir(path), 0755)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = os.WriteFile(path, content, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+
+	expected := make([]string, 0)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		expected = append(expected, filepath.ToSlash(name))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := w.Names(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected get %v, but got %v", expected, names)
+	}
+
+	for _, name := range expected {
+		golden, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, ok := w.Output(name)
+		if !ok {
+			continue
+		}
+		if !bytes.Equal(content, golden) {
+			t.Errorf("Expected get %s\n%s, but got\n%s", name, golden, content)
+		}
+	}
+}
+
+// representative inputs (empty, single day, all equal, outages, gaps, large values, multi-period under-performance, peak hours) rendered in every format, input without mesurement produce no report but a warning
+func TestGoldenCorpus(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	w := writer.NewMemoryWriter()
+	app := NewApplicationWithConfig(reader.NewIOReader("testdata/corpus/input"), w, Config{
+		Formats: Formats,
+	})
+
+	err := app.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "testdata/corpus/output", w)
+
+	expected := "skip empty.json: no mesurements to analyse"
+	if !strings.Contains(logs.String(), expected) {
+		t.Errorf("Expected get %s, but got %s", expected, logs.String())
+	}
+}
+
+// the same corpus with calendar aggregations and peak hours analysis enabled, so their tables are rendered in every format
+func TestGoldenCorpusAnalysis(t *testing.T) {
+	w := writer.NewMemoryWriter()
+	app := NewApplicationWithConfig(reader.NewIOReader("testdata/corpus/input"), w, Config{
+		Aggregations: []Aggregation{AggregationWeek, AggregationMonth, AggregationWeekday},
+		Peak:         &PeakWindow{Start: 20 * 60, End: 22 * 60, Location: time.UTC, Threshold: 0.8},
+		Formats:      Formats,
+	})
+
+	err := app.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "testdata/corpus/analysis", w)
+}
@@ -0,0 +1,113 @@
+package app
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/lineprotocol"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// measurements that analysis result is written as, values are in bytes per second like the input
+const (
+	lineProtocolSummary         = "performance_summary"
+	lineProtocolUnderPerforming = "performance_under_performing"
+	lineProtocolBucket          = "performance_bucket"
+	lineProtocolPeak            = "performance_peak"
+)
+
+func statisticsFields(statistics types.Statistics) []lineprotocol.Field {
+	return []lineprotocol.Field{
+		{Key: "count", Value: int64(statistics.Count)},
+		{Key: "min", Value: statistics.Min},
+		{Key: "max", Value: statistics.Max},
+		{Key: "mean", Value: statistics.Mean},
+		{Key: "median", Value: statistics.Median},
+		{Key: "first_quartile", Value: statistics.FirstQuartile},
+		{Key: "iqr", Value: statistics.IQR},
+	}
+}
+
+// function to render the analysis result as InfluxDB line protocol points, so it can be bulk-loaded into time-series database
+func (a Application) renderLineProtocol(report types.Report) []byte {
+	points := make([]lineprotocol.Point, 0)
+	device := lineprotocol.Tag{Key: "device", Value: report.Name}
+
+	// summary and buckets are stamped with the end of analysed period
+	points = append(points, lineprotocol.Point{
+		Measurement: lineProtocolSummary,
+		Tags:        []lineprotocol.Tag{device},
+		Fields: append(statisticsFields(report.Statistics),
+			lineprotocol.Field{Key: "from", Value: report.From.Format(time.RFC3339)},
+			lineprotocol.Field{Key: "under_performing_periods", Value: int64(len(report.UnderPerformingRanges))},
+		),
+		Time: report.To,
+	})
+
+	// under-performing period is stamped with its first day
+	for _, period := range report.UnderPerformingRanges {
+		points = append(points, lineprotocol.Point{
+			Measurement: lineProtocolUnderPerforming,
+			Tags:        []lineprotocol.Tag{device},
+			Fields: []lineprotocol.Field{
+				{Key: "start", Value: period.From.Format("2006-01-02")},
+				{Key: "end", Value: period.To.Format("2006-01-02")},
+				{Key: "days", Value: int64(period.Days)},
+			},
+			Time: period.From,
+		})
+	}
+
+	for _, aggregation := range report.Aggregations {
+		for _, bucket := range aggregation.Buckets {
+			points = append(points, lineprotocol.Point{
+				Measurement: lineProtocolBucket,
+				Tags: []lineprotocol.Tag{
+					device,
+					{Key: "aggregation", Value: aggregation.Name},
+					{Key: "bucket", Value: bucket.Label},
+				},
+				Fields: append(statisticsFields(bucket.Statistics),
+					lineprotocol.Field{Key: "under_performing_count", Value: int64(bucket.UnderPerformingCount)},
+				),
+				Time: report.To,
+			})
+		}
+	}
+
+	if report.Peak != nil {
+		fields := []lineprotocol.Field{
+			{Key: "peak_count", Value: int64(report.Peak.Peak.Count)},
+			{Key: "peak_mean", Value: report.Peak.Peak.Mean},
+			{Key: "off_peak_count", Value: int64(report.Peak.OffPeak.Count)},
+			{Key: "off_peak_mean", Value: report.Peak.OffPeak.Mean},
+		}
+		// ratio is left out rather than written as 0 (complete outage) when it cannot be computed
+		if report.Peak.RatioAvailable {
+			fields = append(fields, lineprotocol.Field{Key: "ratio", Value: report.Peak.Ratio})
+		}
+		fields = append(fields,
+			lineprotocol.Field{Key: "threshold", Value: report.Peak.Threshold},
+			lineprotocol.Field{Key: "under_performing", Value: report.Peak.UnderPerforming},
+		)
+
+		points = append(points, lineprotocol.Point{
+			Measurement: lineProtocolPeak,
+			Tags: []lineprotocol.Tag{
+				device,
+				{Key: "window", Value: report.Peak.Window},
+				{Key: "timezone", Value: report.Peak.Timezone},
+			},
+			Fields: fields,
+			Time:   report.To,
+		})
+	}
+
+	var buf bytes.Buffer
+	for _, point := range points {
+		buf.WriteString(point.String())
+		buf.WriteByte('\n')
+	}
+
+	return buf.Bytes()
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestRenderLineProtocol(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+
+	report := types.Report{
+		Name:                   "1.json",
+		From:                   time.Date(2021, 3, 1, 0, 0, 0, 0, london),
+		To:                     time.Date(2021, 3, 31, 0, 0, 0, 0, london),
+		Statistics:             types.Statistics{Count: 31, Min: 10, Max: 20, Mean: 15, Median: 15, FirstQuartile: 12, IQR: 6},
+		UnderPerformingPeriods: []string{"2021-03-05", "between 2021-03-27 and 2021-03-29"},
+		UnderPerformingRanges: []types.Period{
+			{From: time.Date(2021, 3, 5, 0, 0, 0, 0, london), To: time.Date(2021, 3, 5, 0, 0, 0, 0, london), Days: 1},
+			{From: time.Date(2021, 3, 27, 0, 0, 0, 0, london), To: time.Date(2021, 3, 29, 0, 0, 0, 0, london), Days: 3},
+		},
+		Aggregations: []types.Aggregation{
+			{Name: "month", Buckets: []types.Bucket{{Label: "2021-03", Statistics: types.Statistics{Count: 31, Mean: 15}, UnderPerformingCount: 4}}},
+		},
+		Peak: &types.PeakAnalysis{Window: "20:00-22:00", Timezone: "Europe/London", Peak: types.Statistics{Count: 2, Mean: 10}, OffPeak: types.Statistics{Count: 29, Mean: 20}, Threshold: 0.8, Ratio: 0.5, RatioAvailable: true, UnderPerforming: true},
+	}
+
+	expected := []string{
+		`performance_summary,device=1.json count=31i,min=10,max=20,mean=15,median=15,first_quartile=12,iqr=6,from="2021-03-01T00:00:00Z",under_performing_periods=2i 1617145200000000000`,
+		`performance_under_performing,device=1.json start="2021-03-05",end="2021-03-05",days=1i 1614902400000000000`,
+		// period crossing the change to daylight saving time is still 3 days
+		`performance_under_performing,device=1.json start="2021-03-27",end="2021-03-29",days=3i 1616803200000000000`,
+		`performance_bucket,aggregation=month,bucket=2021-03,device=1.json count=31i,min=0,max=0,mean=15,median=0,first_quartile=0,iqr=0,under_performing_count=4i 1617145200000000000`,
+		`performance_peak,device=1.json,timezone=Europe/London,window=20:00-22:00 peak_count=2i,peak_mean=10,off_peak_count=29i,off_peak_mean=20,ratio=0.5,threshold=0.8,under_performing=true 1617145200000000000`,
+	}
+
+	content, err := app.Render(report, FormatLineProtocol)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != len(expected) {
+		t.Fatalf("Expected get %d line, but got %d (%s)", len(expected), len(lines), content)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Errorf("Expected get %s, but got %s", expected[i], lines[i])
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	type testcase struct {
+		name     string
+		report   types.Report
+		expected string
+	}
+
+	testcases := []testcase{
+		{
+			name: "Under-performing",
+			report: types.Report{
+				Name:                   "device_1.json",
+				From:                   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:                     time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC),
+				Unit:                   "Megabits per second",
+				UnitExponent:           2,
+				Statistics:             types.Statistics{Mean: 12500000, Min: 125000, Max: 25000000, Median: 12500000},
+				UnderPerformingPeriods: []string{"between 2021-01-02 and 2021-01-03", "2021-01-05"},
+				Aggregations: []types.Aggregation{
+					{Name: "Monthly statistics", Buckets: []types.Bucket{{Label: "2021-01", Statistics: types.Statistics{Mean: 12500000}, UnderPerformingCount: 3}}},
+				},
+			},
+			expected: `# SamKnows Metric Analyser v1.0.0
+
+Report of device\_1.json
+
+## Period checked
+
+- From: 2021-01-01
+- To: 2021-01-05
+
+## Statistics
+
+Unit: Megabits per second
+
+| Average | Min | Max | Median |
+| ---: | ---: | ---: | ---: |
+| 100.00 | 1.00 | 200.00 | 100.00 |
+
+## Under-performing periods
+
+- The period between 2021-01-02 and 2021-01-03 was under-performing.
+- The period 2021-01-05 was under-performing.
+
+## Monthly statistics
+
+| Period | Average | Min | Max | Median | Under-performing |
+| --- | ---: | ---: | ---: | ---: | ---: |
+| 2021-01 | 100.00 | 0.00 | 0.00 | 0.00 | 3 |
+`,
+		},
+		{
+			name: "Peak",
+			report: types.Report{
+				Name:                   "1.json",
+				From:                   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+				To:                     time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC),
+				Unit:                   "Megabits per second",
+				UnitExponent:           2,
+				Statistics:             types.Statistics{Mean: 12500000, Min: 125000, Max: 25000000, Median: 12500000},
+				UnderPerformingPeriods: []string{},
+				Peak: &types.PeakAnalysis{
+					Window:          "20:00-22:00",
+					Timezone:        "UTC",
+					Peak:            types.Statistics{Count: 1, Mean: 125000, Min: 125000, Max: 125000, Median: 125000},
+					OffPeak:         types.Statistics{Count: 2, Mean: 12500000, Min: 12500000, Max: 12500000, Median: 12500000},
+					Threshold:       0.8,
+					Ratio:           0.01,
+					RatioAvailable:  true,
+					UnderPerforming: true,
+				},
+			},
+			expected: `# SamKnows Metric Analyser v1.0.0
+
+Report of 1.json
+
+## Period checked
+
+- From: 2021-01-01
+- To: 2021-01-05
+
+## Statistics
+
+Unit: Megabits per second
+
+| Average | Min | Max | Median |
+| ---: | ---: | ---: | ---: |
+| 100.00 | 1.00 | 200.00 | 100.00 |
+
+## Peak hours (20:00-22:00 UTC)
+
+| Window | Count | Average | Min | Max | Median |
+| --- | ---: | ---: | ---: | ---: | ---: |
+| Peak | 1 | 1.00 | 1.00 | 1.00 | 1.00 |
+| Off-peak | 2 | 100.00 | 100.00 | 100.00 | 100.00 |
+
+Peak/off-peak ratio: 0.01
+
+> Peak hours performance dropped below 80% of off-peak performance.
+`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, err := app.Render(tc.report, FormatMarkdown)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tc.expected {
+				t.Errorf("Expected get %v, but got %v", tc.expected, string(content))
+			}
+		})
+	}
+}
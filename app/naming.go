@@ -0,0 +1,264 @@
+package app
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// name template used when none is configured, input "sub/device.json" is written to "sub/device.output"
+const DefaultNameTemplate = "{{.Path}}.{{.Extension}}"
+
+// what to do when two reports get the same output name
+type CollisionPolicy string
+
+const (
+	// fail before any report is written
+	CollisionError CollisionPolicy = "error"
+	// append -2, -3, ... to the later names
+	CollisionSuffix CollisionPolicy = "suffix"
+)
+
+// function to parse collision policy name provided by user
+func ParseCollisionPolicy(name string) (CollisionPolicy, error) {
+	switch CollisionPolicy(name) {
+	case CollisionError, CollisionSuffix:
+		return CollisionPolicy(name), nil
+	}
+
+	return "", fmt.Errorf("unknown collision policy %q (expected error or suffix)", name)
+}
+
+// what to do when output already exists
+type OverwritePolicy string
+
+const (
+	OverwriteAlways OverwritePolicy = "overwrite"
+	// keep existing output
+	OverwriteSkip OverwritePolicy = "skip"
+	// fail before any report is written
+	OverwriteFail OverwritePolicy = "fail"
+)
+
+// function to parse overwrite policy name provided by user
+func ParseOverwritePolicy(name string) (OverwritePolicy, error) {
+	switch OverwritePolicy(name) {
+	case OverwriteAlways, OverwriteSkip, OverwriteFail:
+		return OverwritePolicy(name), nil
+	}
+
+	return "", fmt.Errorf("unknown overwrite policy %q (expected overwrite, skip or fail)", name)
+}
+
+// how output names are derived from input names, zero value use DefaultNameTemplate, fail on collision and overwrite existing output
+type Naming struct {
+	source    string
+	template  *template.Template
+	Collision CollisionPolicy
+	Overwrite OverwritePolicy
+}
+
+// function to make naming with name template, e.g. "{{.Base}}.{{.Extension}}" to write every report into one directory
+func NewNaming(source string, collision CollisionPolicy, overwrite OverwritePolicy) (Naming, error) {
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(source)
+	if err != nil {
+		return Naming{}, fmt.Errorf("invalid name template: %w", err)
+	}
+
+	// template referring unknown field is rejected now instead of on first report
+	_, err = render(tmpl, nameData{Name: "device.json", Path: "device", Base: "device", Ext: ".json", Format: string(FormatText), Extension: FormatText.Extension()})
+	if err != nil {
+		return Naming{}, fmt.Errorf("invalid name template: %w", err)
+	}
+
+	return Naming{
+		source:    source,
+		template:  tmpl,
+		Collision: collision,
+		Overwrite: overwrite,
+	}, nil
+}
+
+// fields available in name template
+type nameData struct {
+	// input name, e.g. "sub/device.2026-10.json"
+	Name string
+	// directory of input name, e.g. "sub" (empty for input at top level)
+	Dir string
+	// input name without directory and extension, e.g. "device.2026-10"
+	Base string
+	// input name without extension, e.g. "sub/device.2026-10"
+	Path string
+	// extension of input name, e.g. ".json"
+	Ext string
+	// report format, e.g. "text"
+	Format string
+	// file extension of report format, e.g. "output"
+	Extension string
+}
+
+func render(tmpl *template.Template, data nameData) (string, error) {
+	var sb strings.Builder
+	err := tmpl.Execute(&sb, data)
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+var defaultNaming, _ = NewNaming(DefaultNameTemplate, CollisionError, OverwriteAlways)
+
+// function to render output name of input in format
+func (n Naming) outputName(inputName string, format Format) (string, error) {
+	tmpl := n.template
+	if tmpl == nil {
+		tmpl = defaultNaming.template
+	}
+
+	ext := path.Ext(inputName)
+	dir := path.Dir(inputName)
+	if dir == "." {
+		dir = ""
+	}
+
+	name, err := render(tmpl, nameData{
+		Name:      inputName,
+		Dir:       dir,
+		Base:      strings.TrimSuffix(path.Base(inputName), ext),
+		Path:      strings.TrimSuffix(inputName, ext),
+		Ext:       ext,
+		Format:    string(format),
+		Extension: format.Extension(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// output must stay inside the output location
+	cleaned := path.Clean(name)
+	if name == "" || path.IsAbs(cleaned) || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("invalid output name %q of input %s", name, inputName)
+	}
+
+	return cleaned, nil
+}
+
+// owner input of every output name planned by earlier runs of the same application
+type outputOwners struct {
+	mu     sync.Mutex
+	owners map[string]string
+}
+
+// function to make application remember the input that every output belong to across runs (e.g. files processed one by one in watch mode)
+// so output of earlier input is found as collision as well, names are kept in memory and forgotten on restart
+func (a Application) WithOutputTracking() Application {
+	a.tracked = &outputOwners{owners: make(map[string]string)}
+	return a
+}
+
+// function to get input that output was planned for by earlier run
+func (o *outputOwners) owner(name string) (string, bool) {
+	if o == nil {
+		return "", false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	owner, ok := o.owners[name]
+	return owner, ok
+}
+
+// function to record outputs of input
+func (o *outputOwners) store(input string, outputs map[Format]string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, name := range outputs {
+		o.owners[name] = input
+	}
+}
+
+// function to plan output name of every input in every format, collision is failed or suffixed according to policy
+// output of earlier run is a collision only when it belong to other input, so changed input keep its names
+func (a Application) planOutputs(inputs []types.InputFormat, formats []Format) ([]map[Format]string, error) {
+	result := make([]map[Format]string, 0, len(inputs))
+	owners := make(map[string]string)
+	ownerOf := func(name string, input string) (string, bool) {
+		if owner, ok := owners[name]; ok {
+			return owner, true
+		}
+		owner, ok := a.tracked.owner(name)
+		return owner, ok && owner != input
+	}
+
+	for _, input := range inputs {
+		names := make(map[Format]string)
+		for _, format := range formats {
+			name, err := a.config.Naming.outputName(input.Name, format)
+			if err != nil {
+				return nil, err
+			}
+
+			if owner, ok := ownerOf(name, input.Name); ok {
+				if a.config.Naming.Collision != CollisionSuffix {
+					return nil, fmt.Errorf("%s and %s are both written to %s (change name template or use suffix collision policy)", owner, input.Name, name)
+				}
+
+				ext := path.Ext(name)
+				for i := 2; ; i++ {
+					candidate := fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), i, ext)
+					if _, ok := ownerOf(candidate, input.Name); !ok {
+						name = candidate
+						break
+					}
+				}
+			}
+
+			owners[name] = input.Name
+			names[format] = name
+		}
+		result = append(result, names)
+	}
+
+	return result, nil
+}
+
+// function to fail when planned output already exists under fail overwrite policy
+// outputs of skipped input are not checked, they were written by the last run of the same input and are left untouched
+func (a Application) checkOverwrite(outputs []map[Format]string, skipped []bool) error {
+	if a.config.Naming.Overwrite != OverwriteFail {
+		return nil
+	}
+
+	for i, names := range outputs {
+		if skipped[i] {
+			continue
+		}
+		for _, name := range names {
+			exists, err := a.outputExists(name)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("output %s already exists", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// function to check whether output is already written, needed by skip and fail overwrite policy
+func (a Application) outputExists(name string) (bool, error) {
+	checker, ok := a.writer.(writer.ExistenceChecker)
+	if !ok {
+		return false, fmt.Errorf("writer cannot check whether output exists, use overwrite policy")
+	}
+
+	return checker.Exists(name)
+}
@@ -0,0 +1,289 @@
+package app
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// writer that remember written outputs and tell which of them exist
+type mockExistingWriter struct {
+	mockRecordingWriter
+	existing map[string]bool
+}
+
+func (w mockExistingWriter) Exists(name string) (bool, error) {
+	return w.existing[name], nil
+}
+
+func namingInput(name string) types.InputFormat {
+	return types.InputFormat{
+		Name: name,
+		Content: []types.Mesurement{
+			{MetricValue: 1000000, Dtime: types.JSONTime{Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), WallClock: true}},
+		},
+	}
+}
+
+func TestOutputName(t *testing.T) {
+	type testcase struct {
+		name     string
+		template string
+		input    string
+		format   Format
+		expected string
+		err      bool
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Default",
+			template: DefaultNameTemplate,
+			input:    "1.json",
+			format:   FormatText,
+			expected: "1.output",
+		},
+		{
+			name:     "Default keep dots before extension",
+			template: DefaultNameTemplate,
+			input:    "device.2026-10.json",
+			format:   FormatText,
+			expected: "device.2026-10.output",
+		},
+		{
+			name:     "Default mirror directory",
+			template: DefaultNameTemplate,
+			input:    "site/device.json",
+			format:   FormatHTML,
+			expected: "site/device.html",
+		},
+		{
+			name:     "Flatten",
+			template: "{{.Base}}.{{.Format}}.{{.Extension}}",
+			input:    "site/device.json",
+			format:   FormatJSON,
+			expected: "device.json.json",
+		},
+		{
+			name:     "Format directory",
+			template: "{{.Format}}/{{.Path}}.{{.Extension}}",
+			input:    "site/device.json",
+			format:   FormatMarkdown,
+			expected: "markdown/site/device.md",
+		},
+		{
+			name:     "Outside output",
+			template: "../{{.Base}}",
+			input:    "device.json",
+			format:   FormatText,
+			err:      true,
+		},
+		{
+			name:     "Absolute",
+			template: "/tmp/{{.Base}}",
+			input:    "device.json",
+			format:   FormatText,
+			err:      true,
+		},
+		{
+			name:     "Empty",
+			template: "{{.Dir}}",
+			input:    "device.json",
+			format:   FormatText,
+			err:      true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			naming, err := NewNaming(tc.template, CollisionError, OverwriteAlways)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result, err := naming.outputName(tc.input, tc.format)
+			if tc.err {
+				if err == nil {
+					t.Errorf("Expected get error, but got %s", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result != tc.expected {
+				t.Errorf("Expected get %v, but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestNewNamingInvalid(t *testing.T) {
+	for _, source := range []string{"{{.Base", "{{.Unknown}}"} {
+		_, err := NewNaming(source, CollisionError, OverwriteAlways)
+		if err == nil {
+			t.Errorf("Expected get error of %s, but got nil", source)
+		}
+	}
+}
+
+func TestRunNaming(t *testing.T) {
+	type testcase struct {
+		name      string
+		template  string
+		collision CollisionPolicy
+		overwrite OverwritePolicy
+		existing  map[string]bool
+		expected  []string
+		err       string
+	}
+
+	testcases := []testcase{
+		{
+			name:      "Default",
+			template:  DefaultNameTemplate,
+			collision: CollisionError,
+			overwrite: OverwriteAlways,
+			expected:  []string{"device.2026-10.output", "device.2026-11.output", "site/device.2026-10.output"},
+		},
+		{
+			name:      "Collision error",
+			template:  "{{.Base}}.{{.Extension}}",
+			collision: CollisionError,
+			overwrite: OverwriteAlways,
+			err:       "device.2026-10.json and site/device.2026-10.json are both written to device.2026-10.output",
+		},
+		{
+			name:      "Collision suffix",
+			template:  "{{.Base}}.{{.Extension}}",
+			collision: CollisionSuffix,
+			overwrite: OverwriteAlways,
+			expected:  []string{"device.2026-10.output", "device.2026-11.output", "device.2026-10-2.output"},
+		},
+		{
+			name:      "Overwrite skip",
+			template:  DefaultNameTemplate,
+			collision: CollisionError,
+			overwrite: OverwriteSkip,
+			existing:  map[string]bool{"device.2026-11.output": true},
+			expected:  []string{"device.2026-10.output", "site/device.2026-10.output"},
+		},
+		{
+			name:      "Overwrite fail",
+			template:  DefaultNameTemplate,
+			collision: CollisionError,
+			overwrite: OverwriteFail,
+			existing:  map[string]bool{"site/device.2026-10.output": true},
+			err:       "output site/device.2026-10.output already exists",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			naming, err := NewNaming(tc.template, tc.collision, tc.overwrite)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			written := make([]string, 0)
+			reader := mockInputsReader{inputs: []types.InputFormat{
+				namingInput("device.2026-10.json"),
+				namingInput("device.2026-11.json"),
+				namingInput("site/device.2026-10.json"),
+			}}
+			writer := mockExistingWriter{mockRecordingWriter{&written}, tc.existing}
+			app := NewApplicationWithConfig(reader, writer, Config{Naming: naming})
+
+			err = app.Run()
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Expected get %v, but got %v", tc.err, err)
+				}
+				// nothing is written when names are rejected
+				if len(written) != 0 {
+					t.Errorf("Expected get no output, but got %v", written)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(written, tc.expected) {
+				t.Errorf("Expected get %v, but got %v", tc.expected, written)
+			}
+		})
+	}
+}
+
+func TestRunOverwritePolicyUnsupported(t *testing.T) {
+	naming, err := NewNaming(DefaultNameTemplate, CollisionError, OverwriteSkip)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	written := make([]string, 0)
+	app := NewApplicationWithConfig(mockInputsReader{inputs: []types.InputFormat{namingInput("1.json")}}, mockRecordingWriter{&written}, Config{Naming: naming})
+	err = app.Run()
+	if err == nil {
+		t.Errorf("Expected get error, but got nil")
+	}
+}
+
+func TestRunInputsOutputTracking(t *testing.T) {
+	type testcase struct {
+		name      string
+		collision CollisionPolicy
+		expected  []string
+		err       string
+	}
+
+	// inputs are run one by one like files in watch mode, the first one is run twice
+	testcases := []testcase{
+		{
+			name:      "Collision error",
+			collision: CollisionError,
+			expected:  []string{"device.2026-10.output", "device.2026-10.output"},
+			err:       "device.2026-10.json and site/device.2026-10.json are both written to device.2026-10.output",
+		},
+		{
+			name:      "Collision suffix",
+			collision: CollisionSuffix,
+			expected:  []string{"device.2026-10.output", "device.2026-10-2.output", "device.2026-10.output", "device.2026-10-2.output"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			naming, err := NewNaming("{{.Base}}.{{.Extension}}", tc.collision, OverwriteAlways)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			written := make([]string, 0)
+			app := NewApplicationWithConfig(nil, mockRecordingWriter{&written}, Config{Naming: naming}).WithOutputTracking()
+
+			var errs []string
+			for _, name := range []string{"device.2026-10.json", "site/device.2026-10.json", "device.2026-10.json", "site/device.2026-10.json"} {
+				err := app.RunInputs([]types.InputFormat{namingInput(name)})
+				if err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+
+			if !reflect.DeepEqual(written, tc.expected) {
+				t.Errorf("Expected get %v, but got %v", tc.expected, written)
+			}
+			for _, err := range errs {
+				if tc.err == "" || !strings.Contains(err, tc.err) {
+					t.Errorf("Expected get %v, but got %v", tc.err, err)
+				}
+			}
+			if tc.err != "" && len(errs) != 2 {
+				t.Errorf("Expected get 2 errors, but got %v", errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package app
+
+import "fmt"
+
+// rule used to decide whether a mesurement is under-performing
+type OutlierMethod string
+
+const (
+	// below Q1 - multiplier * IQR (Tukey's fences)
+	OutlierIQR OutlierMethod = "iqr"
+	// below mean - multiplier * standard deviation
+	OutlierZScore OutlierMethod = "zscore"
+)
+
+// outlier rule and its multiplier, zero value is Q1 - 1.5 * IQR
+type Outlier struct {
+	Method     OutlierMethod
+	Multiplier float64
+}
+
+// function to parse outlier method name provided by user
+func ParseOutlierMethod(name string) (OutlierMethod, error) {
+	switch OutlierMethod(name) {
+	case OutlierIQR, OutlierZScore:
+		return OutlierMethod(name), nil
+	}
+
+	return "", fmt.Errorf("unknown outlier method %q (expected iqr or zscore)", name)
+}
+
+// function to find the value below which mesurement is treated as under-performing
+func (a Application) findLowerFence(total *summary) float64 {
+	switch a.config.Outlier.Method {
+	case OutlierZScore:
+		multiplier := a.config.Outlier.Multiplier
+		if multiplier == 0 {
+			multiplier = 2
+		}
+
+		return total.mean() - multiplier*total.standardDeviation()
+	default:
+		multiplier := a.config.Outlier.Multiplier
+		if multiplier == 0 {
+			multiplier = 1.5
+		}
+
+		return total.firstQuartile - multiplier*total.IQR
+	}
+}
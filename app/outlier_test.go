@@ -0,0 +1,95 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestFindLowerFence(t *testing.T) {
+	type testcase struct {
+		name    string
+		outlier Outlier
+		result  float64
+	}
+
+	// mean 5, population standard deviation 2, Q1 4, IQR 2
+	input := make([]types.Mesurement, 0)
+	for _, value := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		input = append(input, types.Mesurement{MetricValue: value, Dtime: types.JSONTime{Time: time.Time{}}})
+	}
+	testcases := []testcase{
+		{
+			name:    "Default",
+			outlier: Outlier{},
+			result:  1,
+		},
+		{
+			name:    "IQR",
+			outlier: Outlier{Method: OutlierIQR, Multiplier: 1},
+			result:  2,
+		},
+		{
+			name:    "Z-score",
+			outlier: Outlier{Method: OutlierZScore, Multiplier: 1},
+			result:  3,
+		},
+		{
+			name:    "Z-score default multiplier",
+			outlier: Outlier{Method: OutlierZScore},
+			result:  1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			application := NewApplicationWithConfig(mockReader{}, mockWriter{}, Config{Outlier: tc.outlier})
+			lowerFence := application.findLowerFence(&summarise(input, nil).total)
+			if lowerFence != tc.result {
+				t.Errorf("Expected get %v, but got %v", tc.result, lowerFence)
+			}
+		})
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	type testcase struct {
+		name     string
+		input    string
+		result   string
+		exponent int
+		err      bool
+	}
+
+	testcases := []testcase{
+		{
+			name:   "Auto",
+			input:  "auto",
+			result: "",
+		},
+		{
+			name:     "Megabits",
+			input:    "Mbps",
+			result:   "Megabits per second",
+			exponent: 2,
+		},
+		{
+			name:  "Unknown",
+			input: "MB/s",
+			err:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			unit, err := ParseUnit(tc.input)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, but got %v", tc.err, err)
+			}
+			if unit.name != tc.result || unit.exponent != tc.exponent {
+				t.Errorf("Expected get %s (%d), but got %s (%d)", tc.result, tc.exponent, unit.name, unit.exponent)
+			}
+		})
+	}
+}
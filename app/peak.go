@@ -0,0 +1,124 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// daily window of wall clock time (in Location) that is treated as peak hours, End before Start means the window cross midnight
+type PeakWindow struct {
+	// minutes since midnight, Start inclusive and End exclusive
+	Start    int
+	End      int
+	Location *time.Location
+	// peak average below Threshold * off-peak average is flagged as under-performing
+	Threshold float64
+}
+
+// function to parse window in "HH:MM-HH:MM" format into the start and end of the window, the whole string must match
+func ParsePeakHours(hours string) (int, int, error) {
+	bounds := strings.Split(hours, "-")
+	if len(bounds) != 2 {
+		return 0, 0, fmt.Errorf("invalid peak hours %q (expected HH:MM-HH:MM)", hours)
+	}
+
+	start, err := parseClock(bounds[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid peak hours %q (expected HH:MM-HH:MM): %w", hours, err)
+	}
+	end, err := parseClock(bounds[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid peak hours %q (expected HH:MM-HH:MM): %w", hours, err)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("invalid peak hours %q: window is empty", hours)
+	}
+
+	return start, end, nil
+}
+
+// function to parse wall clock time in "HH:MM" format into minutes since midnight, "24:00" is the end of the day
+func parseClock(clock string) (int, error) {
+	if clock == "24:00" {
+		return 24 * 60, nil
+	}
+
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// window in "HH:MM-HH:MM" format
+func (w PeakWindow) String() string {
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", w.Start/60, w.Start%60, w.End/60, w.End%60)
+}
+
+// function to check whether the time is inside the window (using wall clock time in window location)
+func (w PeakWindow) contains(t time.Time) bool {
+	if w.Location != nil {
+		t = t.In(w.Location)
+	}
+	minute := t.Hour()*60 + t.Minute()
+
+	if w.Start < w.End {
+		return minute >= w.Start && minute < w.End
+	}
+
+	return minute >= w.Start || minute < w.End
+}
+
+// order of peak and off-peak group of the window grouping
+const (
+	offPeakGroup = 0
+	peakGroup    = 1
+)
+
+// function to make grouping that split mesurements into peak and off-peak
+func (w PeakWindow) grouping() *grouping {
+	return newGrouping(func(t time.Time) int {
+		if w.contains(t) {
+			return peakGroup
+		}
+		return offPeakGroup
+	}, func(t time.Time) string {
+		if w.contains(t) {
+			return "peak"
+		}
+		return "off-peak"
+	})
+}
+
+// function to compare statistics of peak and off-peak mesurements from the summarised grouping
+func (w PeakWindow) result(sides *grouping) types.PeakAnalysis {
+	location := time.UTC
+	if w.Location != nil {
+		location = w.Location
+	}
+
+	result := types.PeakAnalysis{
+		Window:    w.String(),
+		Timezone:  location.String(),
+		Threshold: w.Threshold,
+	}
+	if peak := sides.group(peakGroup); peak != nil {
+		result.Peak = peak.summary.statistics()
+	}
+	if offPeak := sides.group(offPeakGroup); offPeak != nil {
+		result.OffPeak = offPeak.summary.statistics()
+	}
+
+	// ratio is meaningless without mesurement on both side (e.g. daily data only have timestamp at midnight)
+	if result.Peak.Count > 0 && result.OffPeak.Count > 0 && result.OffPeak.Mean > 0 {
+		result.Ratio = result.Peak.Mean / result.OffPeak.Mean
+		result.RatioAvailable = true
+		result.UnderPerforming = result.Ratio < w.Threshold
+	}
+
+	return result
+}
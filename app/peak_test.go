@@ -0,0 +1,259 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestParsePeakHours(t *testing.T) {
+	type testcase struct {
+		name  string
+		input string
+		start int
+		end   int
+		err   bool
+	}
+
+	testcases := []testcase{
+		{
+			name:  "Evening",
+			input: "20:00-22:00",
+			start: 20 * 60,
+			end:   22 * 60,
+		},
+		{
+			name:  "Cross midnight",
+			input: "23:30-01:00",
+			start: 23*60 + 30,
+			end:   60,
+		},
+		{
+			name:  "Until midnight",
+			input: "19:00-24:00",
+			start: 19 * 60,
+			end:   24 * 60,
+		},
+		{
+			name:  "Empty window",
+			input: "20:00-20:00",
+			err:   true,
+		},
+		{
+			name:  "Out of range",
+			input: "20:00-25:00",
+			err:   true,
+		},
+		{
+			name:  "Malformed",
+			input: "8pm-10pm",
+			err:   true,
+		},
+		{
+			name:  "Trailing text",
+			input: "20:00-22:00junk",
+			err:   true,
+		},
+		{
+			name:  "Multiple windows",
+			input: "20:00-22:00,23:00-24:00",
+			err:   true,
+		},
+		{
+			name:  "Extra bound",
+			input: "20:00-22:00-23:00",
+			err:   true,
+		},
+		{
+			name:  "Minute out of range",
+			input: "20:60-22:00",
+			err:   true,
+		},
+		{
+			name:  "Past midnight",
+			input: "20:00-24:30",
+			err:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end, err := ParsePeakHours(tc.input)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, but got %v", tc.err, err)
+			}
+			if start != tc.start || end != tc.end {
+				t.Errorf("Expected get %d-%d, but got %d-%d", tc.start, tc.end, start, end)
+			}
+		})
+	}
+}
+
+func TestFindPeakOffPeak(t *testing.T) {
+	type testcase struct {
+		name            string
+		input           []types.Mesurement
+		window          PeakWindow
+		peakCount       int
+		offPeakCount    int
+		ratio           float64
+		ratioAvailable  bool
+		underPerforming bool
+	}
+
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2021-07-01 is in British Summer Time (UTC+1)
+	at := func(value string) types.JSONTime {
+		t, _ := time.Parse(time.RFC3339, value)
+		return types.JSONTime{Time: t}
+	}
+	input := []types.Mesurement{
+		{
+			MetricValue: 50,
+			Dtime:       at("2021-07-01T19:30:00Z"),
+		},
+		{
+			MetricValue: 50,
+			Dtime:       at("2021-07-01T20:30:00Z"),
+		},
+		{
+			MetricValue: 100,
+			Dtime:       at("2021-07-01T21:30:00Z"),
+		},
+		{
+			MetricValue: 100,
+			Dtime:       at("2021-07-01T12:00:00Z"),
+		},
+	}
+	testcases := []testcase{
+		{
+			name:            "UTC",
+			input:           input,
+			window:          PeakWindow{Start: 20 * 60, End: 22 * 60, Location: time.UTC, Threshold: 0.8},
+			peakCount:       2,
+			offPeakCount:    2,
+			ratio:           1,
+			ratioAvailable:  true,
+			underPerforming: false,
+		},
+		{
+			name:            "Local time",
+			input:           input,
+			window:          PeakWindow{Start: 20 * 60, End: 22 * 60, Location: london, Threshold: 0.8},
+			peakCount:       2,
+			offPeakCount:    2,
+			ratio:           0.5,
+			ratioAvailable:  true,
+			underPerforming: true,
+		},
+		{
+			name:            "Cross midnight",
+			input:           input,
+			window:          PeakWindow{Start: 21 * 60, End: 13 * 60, Location: time.UTC, Threshold: 0.8},
+			peakCount:       2,
+			offPeakCount:    2,
+			ratio:           2,
+			ratioAvailable:  true,
+			underPerforming: false,
+		},
+		{
+			name: "Daily mesurement",
+			input: []types.Mesurement{
+				{
+					MetricValue: 1,
+					Dtime:       at("2021-07-01T00:00:00Z"),
+				},
+			},
+			window:          PeakWindow{Start: 20 * 60, End: 22 * 60, Location: time.UTC, Threshold: 0.8},
+			peakCount:       0,
+			offPeakCount:    1,
+			ratio:           0,
+			underPerforming: false,
+		},
+		{
+			name: "Outage during peak",
+			input: []types.Mesurement{
+				{
+					MetricValue: 0,
+					Dtime:       at("2021-07-01T20:30:00Z"),
+				},
+				{
+					MetricValue: 100,
+					Dtime:       at("2021-07-01T12:00:00Z"),
+				},
+			},
+			window:          PeakWindow{Start: 20 * 60, End: 22 * 60, Location: time.UTC, Threshold: 0.8},
+			peakCount:       1,
+			offPeakCount:    1,
+			ratio:           0,
+			ratioAvailable:  true,
+			underPerforming: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			sides := tc.window.grouping()
+			summarise(tc.input, nil, sides)
+			result := tc.window.result(sides)
+			if result.Peak.Count != tc.peakCount {
+				t.Errorf("Expected get %d, but got %d", tc.peakCount, result.Peak.Count)
+			}
+			if result.OffPeak.Count != tc.offPeakCount {
+				t.Errorf("Expected get %d, but got %d", tc.offPeakCount, result.OffPeak.Count)
+			}
+			if result.Ratio != tc.ratio {
+				t.Errorf("Expected get %v, but got %v", tc.ratio, result.Ratio)
+			}
+			if result.RatioAvailable != tc.ratioAvailable {
+				t.Errorf("Expected get %v, but got %v", tc.ratioAvailable, result.RatioAvailable)
+			}
+			if result.UnderPerforming != tc.underPerforming {
+				t.Errorf("Expected get %v, but got %v", tc.underPerforming, result.UnderPerforming)
+			}
+		})
+	}
+}
+
+func TestRenderPeakRatio(t *testing.T) {
+	type testcase struct {
+		name     string
+		peak     types.PeakAnalysis
+		expected string
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Outage during peak",
+			peak:     types.PeakAnalysis{Peak: types.Statistics{Count: 1}, OffPeak: types.Statistics{Count: 1, Mean: 100}, Threshold: 0.8, RatioAvailable: true, UnderPerforming: true},
+			expected: "Peak/off-peak ratio: 0.00",
+		},
+		{
+			name:     "No peak mesurement",
+			peak:     types.PeakAnalysis{OffPeak: types.Statistics{Count: 1, Mean: 100}, Threshold: 0.8},
+			expected: "Peak/off-peak ratio: not enough data",
+		},
+	}
+
+	app := NewApplication(mockReader{}, mockWriter{})
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, format := range []Format{FormatText, FormatMarkdown, FormatHTML} {
+				peak := tc.peak
+				content, err := app.Render(types.Report{Name: "1.json", Peak: &peak}, format)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(content), tc.expected) {
+					t.Errorf("Expected get %s, but got %s", tc.expected, content)
+				}
+			}
+		})
+	}
+}
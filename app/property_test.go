@@ -0,0 +1,289 @@
+package app
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// number of random series every property is checked against
+const propertyRuns = 500
+
+// function to generate random daily series from seed, mixing distributions that hit the edge cases (ties, outages, huge spread)
+func randomSeries(seed int64) []types.Mesurement {
+	r := rand.New(rand.NewSource(seed))
+	n := 1 + r.Intn(200)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	value := func() float64 {
+		switch seed % 4 {
+		case 0:
+			return r.Float64() * 1e8
+		case 1:
+			// few distinct values, many ties
+			return float64(r.Intn(4)) * 1e6
+		case 2:
+			// mostly healthy with outages
+			if r.Intn(10) == 0 {
+				return 0
+			}
+			return 1e7 + r.NormFloat64()*1e5
+		default:
+			// spanning many orders of magnitude
+			return math.Pow(10, r.Float64()*15)
+		}
+	}
+
+	result := make([]types.Mesurement, 0, n)
+	for i := 0; i < n; i++ {
+		result = append(result, types.Mesurement{
+			MetricValue: value(),
+			Dtime:       types.JSONTime{Time: start.AddDate(0, 0, r.Intn(n)), WallClock: true},
+		})
+	}
+
+	return result
+}
+
+func shuffled(seed int64, input []types.Mesurement) []types.Mesurement {
+	result := append([]types.Mesurement(nil), input...)
+	rand.New(rand.NewSource(seed)).Shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+
+	return result
+}
+
+func scaled(input []types.Mesurement, factor float64) []types.Mesurement {
+	result := append([]types.Mesurement(nil), input...)
+	for i := range result {
+		result[i].MetricValue *= factor
+	}
+
+	return result
+}
+
+func closeTo(a float64, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// reference k-th smallest value (from 0) found by counting without sorting
+func kthSmallest(values []float64, k int) float64 {
+	for _, candidate := range values {
+		less, equal := 0, 0
+		for _, value := range values {
+			if value < candidate {
+				less++
+			} else if value == candidate {
+				equal++
+			}
+		}
+		if less <= k && k < less+equal {
+			return candidate
+		}
+	}
+
+	panic("k out of range")
+}
+
+// reference statistics by definition, quartiles use the same positions as summary.quartiles
+func referenceStatistics(input []types.Mesurement) (min float64, max float64, mean float64, median float64, firstQuartile float64, thirdQuartile float64) {
+	values := make([]float64, 0, len(input))
+	for _, mesurement := range input {
+		values = append(values, mesurement.MetricValue)
+	}
+
+	l := len(values)
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	min = kthSmallest(values, 0)
+	max = kthSmallest(values, l-1)
+	mean = sum / float64(l)
+
+	average := func(i int, j int) float64 {
+		return (kthSmallest(values, i) + kthSmallest(values, j)) / 2
+	}
+	switch {
+	case l == 2:
+		return min, max, mean, average(0, 1), min, max
+	case l%2 == 0:
+		return min, max, mean, average(l/2-1, l/2), average(l/4-1, l/4), average(3*l/4-1, 3*l/4)
+	default:
+		return min, max, mean, kthSmallest(values, l/2), kthSmallest(values, l/4), kthSmallest(values, 3*l/4)
+	}
+}
+
+// min, max and mean of the series from the single pass
+func minMaxMean(input []types.Mesurement) (float64, float64, float64) {
+	statistics := summarise(input, nil).total.statistics()
+	return statistics.Min, statistics.Max, statistics.Mean
+}
+
+// median, first quartile and IQR of the series from the single pass
+func quartilesOf(input []types.Mesurement) (float64, float64, float64) {
+	statistics := summarise(input, nil).total.statistics()
+	return statistics.Median, statistics.FirstQuartile, statistics.IQR
+}
+
+// under-performing dates as sorted unix seconds, so series in different order can be compared
+func underPerformingDates(input []types.Mesurement, lowerFence float64) []int64 {
+	result := make([]int64, 0)
+	for _, date := range app.findUnderPerformance(input, summarise(input, nil), lowerFence, nil) {
+		result = append(result, date.Unix())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i] < result[j]
+	})
+
+	return result
+}
+
+func TestStatisticsMatchReference(t *testing.T) {
+	for seed := int64(0); seed < propertyRuns; seed++ {
+		input := randomSeries(seed)
+
+		min, max, mean := minMaxMean(input)
+		median, firstQuartile, iqr := quartilesOf(input)
+		refMin, refMax, refMean, refMedian, refFirstQuartile, refThirdQuartile := referenceStatistics(input)
+
+		if min != refMin || max != refMax || !closeTo(mean, refMean) {
+			t.Errorf("seed %d: Expected get %v %v %v, but got %v %v %v", seed, refMin, refMax, refMean, min, max, mean)
+		}
+		if median != refMedian || firstQuartile != refFirstQuartile || iqr != refThirdQuartile-refFirstQuartile {
+			t.Errorf("seed %d: Expected get %v %v %v, but got %v %v %v", seed, refMedian, refFirstQuartile, refThirdQuartile-refFirstQuartile, median, firstQuartile, iqr)
+		}
+	}
+}
+
+func TestStatisticsOrdered(t *testing.T) {
+	for seed := int64(0); seed < propertyRuns; seed++ {
+		input := randomSeries(seed)
+
+		min, max, mean := minMaxMean(input)
+		median, firstQuartile, iqr := quartilesOf(input)
+		thirdQuartile := firstQuartile + iqr
+
+		// third quartile is rebuilt from IQR, so allow rounding of the addition
+		if !(min <= firstQuartile && firstQuartile <= median && (median <= thirdQuartile || closeTo(median, thirdQuartile)) && (thirdQuartile <= max || closeTo(thirdQuartile, max))) {
+			t.Errorf("seed %d: Expected get min <= Q1 <= median <= Q3 <= max, but got %v %v %v %v %v", seed, min, firstQuartile, median, thirdQuartile, max)
+		}
+		if mean < min || mean > max {
+			t.Errorf("seed %d: Expected get mean between %v and %v, but got %v", seed, min, max, mean)
+		}
+		if iqr < 0 {
+			t.Errorf("seed %d: Expected get IQR >= 0, but got %v", seed, iqr)
+		}
+	}
+}
+
+func TestStatisticsPermutationInvariant(t *testing.T) {
+	for seed := int64(0); seed < propertyRuns; seed++ {
+		input := randomSeries(seed)
+		permuted := shuffled(seed, input)
+
+		min, max, mean := minMaxMean(input)
+		permutedMin, permutedMax, permutedMean := minMaxMean(permuted)
+		// sum in other order may round differently
+		if min != permutedMin || max != permutedMax || !closeTo(mean, permutedMean) {
+			t.Errorf("seed %d: Expected get %v %v %v, but got %v %v %v", seed, min, max, mean, permutedMin, permutedMax, permutedMean)
+		}
+
+		median, firstQuartile, iqr := quartilesOf(input)
+		permutedMedian, permutedFirstQuartile, permutedIQR := quartilesOf(permuted)
+		if median != permutedMedian || firstQuartile != permutedFirstQuartile || iqr != permutedIQR {
+			t.Errorf("seed %d: Expected get %v %v %v, but got %v %v %v", seed, median, firstQuartile, iqr, permutedMedian, permutedFirstQuartile, permutedIQR)
+		}
+
+		lowerFence := firstQuartile - 1.5*iqr
+		dates := underPerformingDates(input, lowerFence)
+		permutedDates := underPerformingDates(permuted, lowerFence)
+		if len(dates) != len(permutedDates) {
+			t.Errorf("seed %d: Expected get %v, but got %v", seed, dates, permutedDates)
+			continue
+		}
+		for i := range dates {
+			if dates[i] != permutedDates[i] {
+				t.Errorf("seed %d: Expected get %v, but got %v", seed, dates, permutedDates)
+				break
+			}
+		}
+	}
+}
+
+func TestStatisticsScalingEquivariant(t *testing.T) {
+	for seed := int64(0); seed < propertyRuns; seed++ {
+		input := randomSeries(seed)
+		min, max, mean := minMaxMean(input)
+		median, firstQuartile, iqr := quartilesOf(input)
+		lowerFence := firstQuartile - 1.5*iqr
+		dates := underPerformingDates(input, lowerFence)
+
+		// power of two keep every value exact, other factor only up to rounding
+		for _, factor := range []float64{0.5, 8, 1024, 0.001, 3.7} {
+			exact := factor == math.Exp2(math.Round(math.Log2(factor)))
+			equal := func(a float64, b float64) bool {
+				if exact {
+					return a == b
+				}
+				return closeTo(a, b)
+			}
+
+			scaledInput := scaled(input, factor)
+			scaledMin, scaledMax, scaledMean := minMaxMean(scaledInput)
+			scaledMedian, scaledFirstQuartile, scaledIQR := quartilesOf(scaledInput)
+			if !equal(scaledMin, min*factor) || !equal(scaledMax, max*factor) || !closeTo(scaledMean, mean*factor) {
+				t.Errorf("seed %d x%v: Expected get %v %v %v, but got %v %v %v", seed, factor, min*factor, max*factor, mean*factor, scaledMin, scaledMax, scaledMean)
+			}
+			if !equal(scaledMedian, median*factor) || !equal(scaledFirstQuartile, firstQuartile*factor) || !(equal(scaledIQR, iqr*factor) || math.Abs(scaledIQR-iqr*factor) <= 1e-9*max*factor) {
+				t.Errorf("seed %d x%v: Expected get %v %v %v, but got %v %v %v", seed, factor, median*factor, firstQuartile*factor, iqr*factor, scaledMedian, scaledFirstQuartile, scaledIQR)
+			}
+
+			// same mesurements are under-performing against the scaled fence
+			if exact {
+				scaledDates := underPerformingDates(scaledInput, scaledFirstQuartile-1.5*scaledIQR)
+				if len(scaledDates) != len(dates) {
+					t.Errorf("seed %d x%v: Expected get %v, but got %v", seed, factor, dates, scaledDates)
+				}
+			}
+		}
+	}
+}
+
+func TestUnderPerformanceMatchFence(t *testing.T) {
+	for seed := int64(0); seed < propertyRuns; seed++ {
+		input := randomSeries(seed)
+		median, firstQuartile, iqr := quartilesOf(input)
+		lowerFence := firstQuartile - 1.5*iqr
+
+		// reference: every mesurement below the fence, in input order
+		expected := make([]time.Time, 0)
+		for _, mesurement := range input {
+			if mesurement.MetricValue < lowerFence {
+				expected = append(expected, mesurement.Dtime.Time)
+			}
+		}
+
+		result := app.findUnderPerformance(input, summarise(input, nil), lowerFence, nil)
+		if len(result) != len(expected) {
+			t.Errorf("seed %d: Expected get %v, but got %v", seed, expected, result)
+			continue
+		}
+		for i := range result {
+			if !result[i].Equal(expected[i]) {
+				t.Errorf("seed %d: Expected get %v, but got %v", seed, expected, result)
+				break
+			}
+		}
+
+		// nothing at or above the median is under-performing
+		if len(result) > 0 && lowerFence > median {
+			t.Errorf("seed %d: Expected get fence below median %v, but got %v", seed, median, lowerFence)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// format that report is rendered in
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatHTML     Format = "html"
+	FormatMarkdown Format = "markdown"
+	// InfluxDB line protocol points
+	FormatLineProtocol Format = "lineprotocol"
+)
+
+// every supported format, in the order of preference when client accept any of them
+var Formats = []Format{FormatJSON, FormatText, FormatHTML, FormatMarkdown, FormatLineProtocol}
+
+// function to parse report format name provided by user
+func ParseFormat(name string) (Format, error) {
+	for _, format := range Formats {
+		if Format(name) == format {
+			return format, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown format %q (expected text, json, html, markdown or lineprotocol)", name)
+}
+
+// file extension of the report written in the format
+func (f Format) Extension() string {
+	switch f {
+	case FormatText:
+		return "output"
+	case FormatMarkdown:
+		return "md"
+	case FormatLineProtocol:
+		return "lp"
+	}
+
+	return string(f)
+}
+
+// media type of the report rendered in the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSON:
+		return "application/json"
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	}
+
+	return "text/plain; charset=utf-8"
+}
+
+// function to render the analysis result in the format, with template provided in config or the default template
+func (a Application) Render(report types.Report, format Format) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(report, "", "  ")
+	case FormatLineProtocol:
+		return a.renderLineProtocol(report), nil
+	}
+
+	if tmpl, ok := a.config.Templates[format]; ok {
+		return tmpl.render(report)
+	}
+	if tmpl, ok := defaultTemplates[format]; ok {
+		return tmpl.render(report)
+	}
+
+	return nil, fmt.Errorf("unknown format %q", format)
+}
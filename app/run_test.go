@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
+)
+
+// input tree that Run is tested over, noise files must be skipped
+var runFixtures = fstest.MapFS{
+	".gitkeep":  {Data: []byte{}},
+	"notes.txt": {Data: []byte("not input")},
+	"1.json": {Data: []byte(`[
+		{"metricValue": 12134364.2441124, "dtime": "2021-01-01"},
+		{"metricValue": 12847433.736937232, "dtime": "2021-01-02"},
+		{"metricValue": 12763774.618976614, "dtime": "2021-01-03"},
+		{"metricValue": 12255069.025739422, "dtime": "2021-01-04"},
+		{"metricValue": 12495435.087091941, "dtime": "2021-01-05"},
+		{"metricValue": 12449491.064788738, "dtime": "2021-01-06"},
+		{"metricValue": 12651592.972722763, "dtime": "2021-01-07"},
+		{"metricValue": 12788723.351135513, "dtime": "2021-01-08"},
+		{"metricValue": 12093859.586774236, "dtime": "2021-01-09"},
+		{"metricValue": 12028347.476522006, "dtime": "2021-01-10"}
+	]`)},
+	"device.2026-10.json": {Data: []byte(`[
+		{"metricValue": 5000000, "dtime": "2026-10-01T20:00:00Z"},
+		{"metricValue": 5100000, "dtime": "2026-10-02T20:00:00Z"},
+		{"metricValue": 4900000, "dtime": "2026-10-03T20:00:00Z"},
+		{"metricValue": 5050000, "dtime": "2026-10-04T20:00:00Z"},
+		{"metricValue": 200000, "dtime": "2026-10-05T20:00:00Z"},
+		{"metricValue": 4950000, "dtime": "2026-10-06T20:00:00Z"}
+	]`)},
+	"site/device.2026-11.json": {Data: []byte(`[
+		{"metricValue": 900000, "dtime": "2026-11-01T08:00:00Z"},
+		{"metricValue": 910000, "dtime": "2026-11-01T21:00:00Z"},
+		{"metricValue": 905000, "dtime": "2026-11-02T08:00:00Z"},
+		{"metricValue": 20000, "dtime": "2026-11-02T21:00:00Z"},
+		{"metricValue": 895000, "dtime": "2026-11-03T08:00:00Z"},
+		{"metricValue": 15000, "dtime": "2026-11-03T21:00:00Z"},
+		{"metricValue": 900000, "dtime": "2026-11-04T08:00:00Z"}
+	]`)},
+}
+
+func TestRunGolden(t *testing.T) {
+	options := reader.DefaultIOOptions()
+	options.Recursive = true
+	w := writer.NewMemoryWriter()
+	app := NewApplicationWithConfig(reader.NewFSReader(runFixtures, options), w, Config{
+		Formats: Formats,
+	})
+
+	err := app.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertGolden(t, "testdata/run", w)
+
+	// structured report is written under input name without extension
+	report, ok := w.Report("site/device.2026-11")
+	if !ok || report.Statistics.Count != 7 {
+		t.Errorf("Expected get report of 7 mesurement, but got %v", report)
+	}
+}
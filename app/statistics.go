@@ -0,0 +1,308 @@
+package app
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// accumulator of one group of mesurements (whole series, calendar bucket or peak side), every field is updated while each mesurement is added once
+type summary struct {
+	count int
+	min   float64
+	max   float64
+	sum   float64
+	from  time.Time
+	to    time.Time
+	// running mean and sum of squared deviation from it (Welford), so standard deviation need no second walk of the values
+	runningMean float64
+	squares     float64
+
+	// selected from the values of the group after the pass
+	median        float64
+	firstQuartile float64
+	IQR           float64
+	// mesurements below the lower fence, counted by series.below
+	underPerforming int
+}
+
+// function to add one mesurement to the summary
+func (s *summary) add(value float64, t time.Time) {
+	// start from the first value, so zero value (outage) is not mistaken for unset min
+	if s.count == 0 {
+		s.min, s.max = value, value
+		s.from, s.to = t, t
+	} else {
+		if value < s.min {
+			s.min = value
+		}
+		if value > s.max {
+			s.max = value
+		}
+		if t.Before(s.from) {
+			s.from = t
+		}
+		if t.After(s.to) {
+			s.to = t
+		}
+	}
+
+	s.count++
+	s.sum += value
+	delta := value - s.runningMean
+	s.runningMean += delta / float64(s.count)
+	s.squares += delta * (value - s.runningMean)
+}
+
+// function to get average of the values
+func (s *summary) mean() float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	return s.sum / float64(s.count)
+}
+
+// function to get population standard deviation of the values
+func (s *summary) standardDeviation() float64 {
+	if s.count == 0 {
+		return 0
+	}
+
+	return math.Sqrt(s.squares / float64(s.count))
+}
+
+// function to select median, first quartile and IQR from every value of the group, values are reordered
+func (s *summary) quartiles(values []float64) {
+	var thirdQuartile float64
+	l := len(values)
+	if l == 0 {
+		return
+	} else if l == 2 {
+		// not enough element to take average around quartile position
+		selectPositions(values, []int{0, 1})
+		s.median = (values[0] + values[1]) / 2
+		s.firstQuartile = values[0]
+		thirdQuartile = values[1]
+	} else if l%2 == 0 {
+		// only values around quartile positions are needed, so they are selected instead of sorting the whole dataset
+		selectPositions(values, []int{l/4 - 1, l / 4, l/2 - 1, l / 2, 3*l/4 - 1, 3 * l / 4})
+		s.median = (values[l/2-1] + values[l/2]) / 2
+		s.firstQuartile = (values[l/4-1] + values[l/4]) / 2
+		thirdQuartile = (values[3*l/4-1] + values[3*l/4]) / 2
+	} else {
+		selectPositions(values, []int{l / 4, l / 2, 3 * l / 4})
+		s.median = values[l/2]
+		s.firstQuartile = values[l/4]
+		thirdQuartile = values[3*l/4]
+	}
+	s.IQR = thirdQuartile - s.firstQuartile
+}
+
+// function to get statistics of the summary
+func (s *summary) statistics() types.Statistics {
+	return types.Statistics{
+		Count:         s.count,
+		Min:           s.min,
+		Max:           s.max,
+		Mean:          s.mean(),
+		Median:        s.median,
+		FirstQuartile: s.firstQuartile,
+		IQR:           s.IQR,
+	}
+}
+
+// mesurements split into groups (e.g. calendar buckets, peak and off-peak)
+type grouping struct {
+	// function to find order of the group that time belong to, order is unique per group
+	orderOf func(t time.Time) int
+	// function to find label of the group, only called for the first mesurement of the group
+	labelOf func(t time.Time) string
+	index   map[int]int32
+	groups  []*group
+	// group of every mesurement, in mesurement order
+	ids []int32
+}
+
+// one group of grouping
+type group struct {
+	label   string
+	order   int
+	summary summary
+}
+
+func newGrouping(orderOf func(t time.Time) int, labelOf func(t time.Time) string) *grouping {
+	return &grouping{
+		orderOf: orderOf,
+		labelOf: labelOf,
+		index:   make(map[int]int32),
+	}
+}
+
+// function to add one mesurement to its group
+func (g *grouping) add(value float64, t time.Time) {
+	order := g.orderOf(t)
+	id, ok := g.index[order]
+	if !ok {
+		id = int32(len(g.groups))
+		g.index[order] = id
+		g.groups = append(g.groups, &group{label: g.labelOf(t), order: order})
+	}
+
+	g.groups[id].summary.add(value, t)
+	g.ids = append(g.ids, id)
+}
+
+// function to get group by order, nil when no mesurement belong to it
+func (g *grouping) group(order int) *group {
+	id, ok := g.index[order]
+	if !ok {
+		return nil
+	}
+
+	return g.groups[id]
+}
+
+// function to list groups by order
+func (g *grouping) ordered() []*group {
+	result := append([]*group(nil), g.groups...)
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].order < result[j].order
+	})
+
+	return result
+}
+
+// result of the single pass over mesurement series
+// metric values are kept once in mesurement order, quartiles of every group are selected from them and outliers are found by walking them once
+type series struct {
+	total     summary
+	values    []float64
+	groupings []*grouping
+}
+
+// function to summarise the whole series and split it by groupings in one pass, time is localised into location (kept as is when nil) before it is grouped
+func summarise(input []types.Mesurement, location *time.Location, groupings ...*grouping) *series {
+	s := &series{
+		values:    make([]float64, 0, len(input)),
+		groupings: groupings,
+	}
+	for _, g := range groupings {
+		g.ids = make([]int32, 0, len(input))
+	}
+
+	for _, mesurement := range input {
+		value := mesurement.MetricValue
+		t := mesurement.Dtime.Time
+		if location != nil {
+			t = mesurement.Dtime.Localise(location).Time
+		}
+
+		s.total.add(value, t)
+		s.values = append(s.values, value)
+		for _, g := range groupings {
+			g.add(value, t)
+		}
+	}
+
+	s.selectQuartiles()
+	return s
+}
+
+// function to select quartiles of the series and every group
+// values of each grouping are gathered group by group into one reused buffer, so values are not kept per group and stay in mesurement order
+func (s *series) selectQuartiles() {
+	scratch := make([]float64, len(s.values))
+
+	for _, g := range s.groupings {
+		offsets := make([]int, len(g.groups)+1)
+		for i, group := range g.groups {
+			offsets[i+1] = offsets[i] + group.summary.count
+		}
+		next := append([]int(nil), offsets...)
+		for i, value := range s.values {
+			id := g.ids[i]
+			scratch[next[id]] = value
+			next[id]++
+		}
+		for i, group := range g.groups {
+			group.summary.quartiles(scratch[offsets[i]:offsets[i+1]])
+		}
+	}
+
+	copy(scratch, s.values)
+	s.total.quartiles(scratch)
+}
+
+// function to find position of every mesurement below the lower fence in mesurement order and count them in the series and every group
+func (s *series) below(lowerFence float64) []int {
+	result := make([]int, 0)
+	// nothing can be below fence that is not above the min
+	if s.total.count == 0 || lowerFence <= s.total.min {
+		return result
+	}
+
+	for i, value := range s.values {
+		if value >= lowerFence {
+			continue
+		}
+
+		result = append(result, i)
+		s.total.underPerforming++
+		for _, g := range s.groupings {
+			g.groups[g.ids[i]].summary.underPerforming++
+		}
+	}
+
+	return result
+}
+
+// function to partially reorder values so value at every position (ascending, duplicate allowed) is the same as if values were sorted, in linear time on average
+func selectPositions(values []float64, positions []int) {
+	lo, hi := 0, len(values)
+	for len(positions) > 0 && hi-lo > 1 {
+		if hi-lo <= 12 {
+			sort.Float64s(values[lo:hi])
+			return
+		}
+
+		// three-way partition around median of three, so many equal values (e.g. ties, outages) do not degrade it
+		a, b, c := values[lo], values[lo+(hi-lo)/2], values[hi-1]
+		pivot := math.Max(math.Min(a, b), math.Min(math.Max(a, b), c))
+		lt, i, gt := lo, lo, hi
+		for i < gt {
+			if values[i] < pivot {
+				values[lt], values[i] = values[i], values[lt]
+				lt++
+				i++
+			} else if values[i] > pivot {
+				gt--
+				values[gt], values[i] = values[i], values[gt]
+			} else {
+				i++
+			}
+		}
+
+		// positions in [lt, gt) hold the pivot already, positions on the left are selected recursively and on the right by the loop
+		left := sort.SearchInts(positions, lt)
+		selectPositions(values[lo:lt], shift(positions[:left], lo))
+		right := sort.SearchInts(positions, gt)
+		positions = positions[right:]
+		lo = gt
+	}
+}
+
+// function to make positions relative to start of sub slice
+func shift(positions []int, offset int) []int {
+	if offset == 0 {
+		return positions
+	}
+
+	result := make([]int, len(positions))
+	for i, position := range positions {
+		result[i] = position - offset
+	}
+	return result
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestSelectPositions(t *testing.T) {
+	type testcase struct {
+		name  string
+		input []float64
+	}
+
+	r := rand.New(rand.NewSource(1))
+	random := make([]float64, 1000)
+	ties := make([]float64, 1000)
+	for i := range random {
+		random[i] = r.Float64()
+		ties[i] = float64(r.Intn(3))
+	}
+	ascending := make([]float64, 1000)
+	descending := make([]float64, 1000)
+	equal := make([]float64, 1000)
+	for i := range ascending {
+		ascending[i] = float64(i)
+		descending[i] = float64(len(descending) - i)
+		equal[i] = 7
+	}
+
+	testcases := []testcase{
+		{name: "Small", input: []float64{3, 1, 2}},
+		{name: "Random", input: random},
+		{name: "Ties", input: ties},
+		{name: "Ascending", input: ascending},
+		{name: "Descending", input: descending},
+		{name: "All equal", input: equal},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := len(tc.input)
+			positions := []int{0, l / 4, l / 4, l / 2, 3 * l / 4, l - 1}
+			sorted := append([]float64(nil), tc.input...)
+			sort.Float64s(sorted)
+
+			values := append([]float64(nil), tc.input...)
+			selectPositions(values, positions)
+			for _, position := range positions {
+				if values[position] != sorted[position] {
+					t.Errorf("Expected get %v, but got %v", sorted[position], values[position])
+				}
+			}
+		})
+	}
+}
+
+func TestSummary(t *testing.T) {
+	type testcase struct {
+		name      string
+		input     []float64
+		result    types.Statistics
+		deviation float64
+		below     int
+	}
+
+	testcases := []testcase{
+		{
+			name:   "Empty",
+			input:  []float64{},
+			result: types.Statistics{},
+		},
+		{
+			name:      "Outage first",
+			input:     []float64{0, 4, 4, 4, 5, 5, 7, 9},
+			result:    types.Statistics{Count: 8, Min: 0, Max: 9, Mean: 4.75, Median: 4.5, FirstQuartile: 4, IQR: 2},
+			deviation: 2.436698586202241,
+			below:     1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+			input := make([]types.Mesurement, 0, len(tc.input))
+			// added in reverse date order, range is still from the earliest to the latest
+			for i, value := range tc.input {
+				input = append(input, types.Mesurement{MetricValue: value, Dtime: types.JSONTime{Time: start.AddDate(0, 0, len(tc.input)-i)}})
+			}
+
+			s := summarise(input, nil)
+			if s.total.statistics() != tc.result {
+				t.Errorf("Expected get %v, but got %v", tc.result, s.total.statistics())
+			}
+			if deviation := s.total.standardDeviation(); deviation-tc.deviation > 1e-12 || tc.deviation-deviation > 1e-12 {
+				t.Errorf("Expected get %v, but got %v", tc.deviation, deviation)
+			}
+			if below := s.below(1); len(below) != tc.below || s.total.underPerforming != tc.below {
+				t.Errorf("Expected get %v, but got %v", tc.below, below)
+			}
+			if len(input) > 0 && (!s.total.from.Equal(start.AddDate(0, 0, 1)) || !s.total.to.Equal(start.AddDate(0, 0, len(input)))) {
+				t.Errorf("Expected get %v - %v, but got %v - %v", start.AddDate(0, 0, 1), start.AddDate(0, 0, len(input)), s.total.from, s.total.to)
+			}
+		})
+	}
+}
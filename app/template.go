@@ -0,0 +1,142 @@
+package app
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// default layouts, copy one of them as a starting point of custom template
+//
+//go:embed templates
+var templateFiles embed.FS
+
+// template that report is rendered with, it receives types.Report as data
+type Template struct {
+	source   string
+	template interface {
+		Execute(w io.Writer, data any) error
+	}
+}
+
+// escape characters that would break Markdown table cell or start formatting
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`, "`", "\\`")
+
+// helper functions available in every template
+var templateFuncs = map[string]any{
+	// date in YYYY-MM-DD format
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	// "YYYY-MM-DD to YYYY-MM-DD", or one date when both are on the same day
+	"dateRange": func(from time.Time, to time.Time) string {
+		if sameDate(from, to) {
+			return from.Format("2006-01-02")
+		}
+		return fmt.Sprintf("%s to %s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	},
+	"percent": func(v float64) float64 {
+		return v * 100
+	},
+	"join": strings.Join,
+	// value in bytes per second formatted in the report unit, e.g. "99.48"
+	"scale": func(report types.Report, value float64) string {
+		return fmt.Sprintf("%.2f", report.Scale(value))
+	},
+	// value in bytes per second formatted in the unit that suit the value, e.g. "99.48 Megabits per second"
+	"speed": func(value float64) string {
+		unit, exponent := Application{}.findOptimalUnit(value)
+		return fmt.Sprintf("%.2f %s", types.Report{UnitExponent: exponent}.Scale(value), unit)
+	},
+	"md": markdownEscaper.Replace,
+	// aligned plain text table of aggregation buckets
+	"bucketTable": func(report types.Report, aggregation types.Aggregation) string {
+		var sb strings.Builder
+		tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "    Period\tAverage\tMin\tMax\tMedian\tUnder-performing")
+		for _, bucket := range aggregation.Buckets {
+			fmt.Fprintf(tw, "    %s\t%.2f\t%.2f\t%.2f\t%.2f\t%d\n", bucket.Label, report.Scale(bucket.Mean), report.Scale(bucket.Min), report.Scale(bucket.Max), report.Scale(bucket.Median), bucket.UnderPerformingCount)
+		}
+		tw.Flush()
+		return strings.TrimSuffix(sb.String(), "\n")
+	},
+	// aligned plain text table of peak and off-peak statistics
+	"peakTable": func(report types.Report, peak types.PeakAnalysis) string {
+		var sb strings.Builder
+		tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "    Window\tCount\tAverage\tMin\tMax\tMedian")
+		for _, row := range []struct {
+			name       string
+			statistics types.Statistics
+		}{{"Peak", peak.Peak}, {"Off-peak", peak.OffPeak}} {
+			fmt.Fprintf(tw, "    %s\t%d\t%.2f\t%.2f\t%.2f\t%.2f\n", row.name, row.statistics.Count, report.Scale(row.statistics.Mean), report.Scale(row.statistics.Min), report.Scale(row.statistics.Max), report.Scale(row.statistics.Median))
+		}
+		tw.Flush()
+		return strings.TrimSuffix(sb.String(), "\n")
+	},
+}
+
+// function to parse template of format, html/template is used for HTML so values are escaped
+func ParseTemplate(format Format, source string) (Template, error) {
+	switch format {
+	case FormatHTML:
+		tmpl, err := htmltemplate.New("report").Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return Template{}, err
+		}
+		return Template{source: source, template: tmpl}, nil
+	case FormatText, FormatMarkdown:
+		tmpl, err := template.New("report").Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return Template{}, err
+		}
+		return Template{source: source, template: tmpl}, nil
+	}
+
+	return Template{}, fmt.Errorf("format %s does not support template", format)
+}
+
+var defaultTemplates = map[Format]Template{
+	FormatText:     mustParseDefaultTemplate(FormatText, "templates/text.tmpl"),
+	FormatHTML:     mustParseDefaultTemplate(FormatHTML, "templates/html.tmpl"),
+	FormatMarkdown: mustParseDefaultTemplate(FormatMarkdown, "templates/markdown.tmpl"),
+}
+
+func mustParseDefaultTemplate(format Format, name string) Template {
+	source, err := templateFiles.ReadFile(name)
+	if err != nil {
+		panic(err)
+	}
+
+	tmpl, err := ParseTemplate(format, string(source))
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// function to get source of default template of format
+func DefaultTemplate(format Format) (string, bool) {
+	tmpl, ok := defaultTemplates[format]
+	return tmpl.source, ok
+}
+
+// function to render report with template
+func (t Template) render(report types.Report) ([]byte, error) {
+	var buf bytes.Buffer
+
+	err := t.template.Execute(&buf, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestParseTemplate(t *testing.T) {
+	type testcase struct {
+		name   string
+		format Format
+		source string
+		err    bool
+	}
+
+	testcases := []testcase{
+		{
+			name:   "Text",
+			format: FormatText,
+			source: "{{.Name}}",
+		},
+		{
+			name:   "Syntax error",
+			format: FormatText,
+			source: "{{.Name",
+			err:    true,
+		},
+		{
+			name:   "Unknown function",
+			format: FormatMarkdown,
+			source: "{{bold .Name}}",
+			err:    true,
+		},
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			source: "{{.Name}}",
+			err:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseTemplate(tc.format, tc.source)
+			if (err != nil) != tc.err {
+				t.Errorf("Expected error %v, but got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	report := types.Report{
+		Name:                   "<device>",
+		From:                   time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:                     time.Date(2021, 1, 5, 0, 0, 0, 0, time.UTC),
+		Unit:                   "Megabits per second",
+		UnitExponent:           2,
+		Statistics:             types.Statistics{Mean: 12435327.330777813, Min: 250},
+		UnderPerformingPeriods: []string{"2021-01-02", "2021-01-04"},
+	}
+
+	type testcase struct {
+		name     string
+		format   Format
+		source   string
+		expected string
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Text helpers",
+			format:   FormatText,
+			source:   `{{.Name}} {{dateRange .From .To}} {{dateRange .From .From}}: {{scale . .Statistics.Mean}} {{.Unit}} (min {{speed .Statistics.Min}}), {{len .UnderPerformingPeriods}} periods: {{join .UnderPerformingPeriods "; "}}`,
+			expected: `<device> 2021-01-01 to 2021-01-05 2021-01-01: 99.48 Megabits per second (min 2.00 Kilobits per second), 2 periods: 2021-01-02; 2021-01-04`,
+		},
+		{
+			name:     "HTML escape",
+			format:   FormatHTML,
+			source:   `<h1>{{.Name}}</h1>`,
+			expected: `<h1>&lt;device&gt;</h1>`,
+		},
+		{
+			name:     "Markdown escape",
+			format:   FormatMarkdown,
+			source:   `# {{md "a_b|c"}}`,
+			expected: `# a\_b\|c`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate(tc.format, tc.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			application := NewApplicationWithConfig(mockReader{}, mockWriter{}, Config{Templates: map[Format]Template{tc.format: tmpl}})
+			content, err := application.Render(report, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tc.expected {
+				t.Errorf("Expected get %v, but got %v", tc.expected, string(content))
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package app
+
+import "time"
+
+// function to find the timezone that input is analysed in (per input override, then global timezone, then UTC)
+func (a Application) locationOf(name string) *time.Location {
+	if location, ok := a.config.InputLocations[name]; ok && location != nil {
+		return location
+	}
+
+	if a.config.Location != nil {
+		return a.config.Location
+	}
+
+	return time.UTC
+}
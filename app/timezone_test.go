@@ -0,0 +1,279 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestLocationOf(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+
+	type testcase struct {
+		name   string
+		config Config
+		input  string
+		result *time.Location
+	}
+
+	testcases := []testcase{
+		{
+			name:   "Default",
+			config: Config{},
+			input:  "1.json",
+			result: time.UTC,
+		},
+		{
+			name:   "Global",
+			config: Config{Location: london},
+			input:  "1.json",
+			result: london,
+		},
+		{
+			name:   "Per input",
+			config: Config{Location: london, InputLocations: map[string]*time.Location{"1.json": tokyo}},
+			input:  "1.json",
+			result: tokyo,
+		},
+		{
+			name:   "Other input",
+			config: Config{Location: london, InputLocations: map[string]*time.Location{"1.json": tokyo}},
+			input:  "2.json",
+			result: london,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			location := NewApplicationWithConfig(mockReader{}, mockWriter{}, tc.config).locationOf(tc.input)
+			if location != tc.result {
+				t.Errorf("Expected get %v, but got %v", tc.result, location)
+			}
+		})
+	}
+}
+
+func TestLocalise(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+
+	type testcase struct {
+		name   string
+		input  types.JSONTime
+		result time.Time
+	}
+
+	utc, _ := time.Parse(time.RFC3339, "2021-03-31T23:30:00Z")
+	testcases := []testcase{
+		{
+			name:   "Date only",
+			input:  types.JSONTime{Time: time.Date(2021, 3, 28, 0, 0, 0, 0, time.UTC), WallClock: true},
+			result: time.Date(2021, 3, 28, 0, 0, 0, 0, london),
+		},
+		{
+			name:   "With UTC offset",
+			input:  types.JSONTime{Time: utc},
+			result: time.Date(2021, 4, 1, 0, 30, 0, 0, london),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := summarise([]types.Mesurement{{MetricValue: 1, Dtime: tc.input}}, london).total.from
+			if !result.Equal(tc.result) {
+				t.Errorf("Expected get %v, but got %v", tc.result, result)
+			}
+			if result.Location() != london {
+				t.Errorf("Expected get %v, but got %v", london, result.Location())
+			}
+		})
+	}
+}
+
+func TestDateArrayConcatStringDaylightSaving(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+
+	type testcase struct {
+		name   string
+		input  []time.Time
+		result []string
+	}
+
+	// UTC midnight is 00:00 local time before the change and 01:00 local time after it
+	utcMidnight := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).In(london)
+	}
+	testcases := []testcase{
+		{
+			name: "Spring forward",
+			input: []time.Time{
+				time.Date(2021, 3, 27, 0, 0, 0, 0, london),
+				time.Date(2021, 3, 28, 0, 0, 0, 0, london),
+				time.Date(2021, 3, 29, 0, 0, 0, 0, london),
+			},
+			result: []string{"between 2021-03-27 and 2021-03-29"},
+		},
+		{
+			name: "Spring forward from UTC",
+			input: []time.Time{
+				utcMidnight(2021, 3, 27),
+				utcMidnight(2021, 3, 28),
+				utcMidnight(2021, 3, 29),
+				utcMidnight(2021, 3, 31),
+			},
+			result: []string{"between 2021-03-27 and 2021-03-29", "2021-03-31"},
+		},
+		{
+			name: "Fall back",
+			input: []time.Time{
+				time.Date(2021, 10, 30, 0, 0, 0, 0, london),
+				time.Date(2021, 10, 31, 0, 0, 0, 0, london),
+				time.Date(2021, 11, 1, 0, 0, 0, 0, london),
+			},
+			result: []string{"between 2021-10-30 and 2021-11-01"},
+		},
+		{
+			name: "Fall back from UTC",
+			input: []time.Time{
+				utcMidnight(2021, 10, 30),
+				utcMidnight(2021, 10, 31),
+				utcMidnight(2021, 11, 1),
+			},
+			result: []string{"between 2021-10-30 and 2021-11-01"},
+		},
+		{
+			name: "Same date",
+			input: []time.Time{
+				time.Date(2021, 10, 31, 0, 30, 0, 0, london),
+				time.Date(2021, 10, 31, 1, 30, 0, 0, london),
+				time.Date(2021, 10, 31, 23, 30, 0, 0, london),
+			},
+			result: []string{"2021-10-31"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dateStrings := app.DateArrayConcatString(tc.input)
+			if len(dateStrings) != len(tc.result) {
+				t.Fatalf("Expected get %v, but got %v", tc.result, dateStrings)
+			}
+			for i, dateString := range dateStrings {
+				if dateString != tc.result[i] {
+					t.Errorf("Expected get %v, but got %v", tc.result, dateStrings)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyseTimezone(t *testing.T) {
+	london, _ := time.LoadLocation("Europe/London")
+	application := NewApplicationWithConfig(mockReader{}, mockWriter{}, Config{
+		Location:     london,
+		Aggregations: []Aggregation{AggregationMonth},
+	})
+
+	// 2021-03-31T23:30:00Z is already April in London (BST)
+	at := func(value string) types.JSONTime {
+		t, _ := time.Parse(time.RFC3339, value)
+		return types.JSONTime{Time: t}
+	}
+	report := application.Analyse(types.InputFormat{
+		Name: "1.json",
+		Content: []types.Mesurement{
+			{
+				MetricValue: 10,
+				Dtime:       at("2021-03-30T12:00:00Z"),
+			},
+			{
+				MetricValue: 10,
+				Dtime:       at("2021-03-31T23:30:00Z"),
+			},
+		},
+	})
+
+	if report.To.Format("2006-01-02") != "2021-04-01" {
+		t.Errorf("Expected get %v, but got %v", "2021-04-01", report.To.Format("2006-01-02"))
+	}
+	buckets := report.Aggregations[0].Buckets
+	if len(buckets) != 2 || buckets[0].Label != "2021-03" || buckets[1].Label != "2021-04" {
+		t.Errorf("Expected get 2021-03 and 2021-04 buckets, but got %v", buckets)
+	}
+}
+
+func TestSkippedMidnight(t *testing.T) {
+	// clocks moved from 00:00 to 01:00 on 2018-11-04, so that date has no midnight
+	saoPaulo, _ := time.LoadLocation("America/Sao_Paulo")
+
+	type testcase struct {
+		name   string
+		input  []time.Time
+		dates  []time.Time
+		result []string
+	}
+
+	testcases := []testcase{
+		{
+			name: "Date without midnight",
+			input: []time.Time{
+				time.Date(2018, 11, 4, 12, 0, 0, 0, saoPaulo),
+			},
+			dates:  []time.Time{time.Date(2018, 11, 4, 1, 0, 0, 0, saoPaulo)},
+			result: []string{"2018-11-04"},
+		},
+		{
+			name: "Joined with dates around it",
+			input: []time.Time{
+				time.Date(2018, 11, 3, 12, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 4, 12, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 5, 12, 0, 0, 0, saoPaulo),
+			},
+			dates: []time.Time{
+				time.Date(2018, 11, 3, 0, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 4, 1, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 5, 0, 0, 0, 0, saoPaulo),
+			},
+			result: []string{"between 2018-11-03 and 2018-11-05"},
+		},
+		{
+			name: "Gap after date without midnight",
+			input: []time.Time{
+				time.Date(2018, 11, 3, 12, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 4, 12, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 6, 12, 0, 0, 0, saoPaulo),
+			},
+			dates: []time.Time{
+				time.Date(2018, 11, 3, 0, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 4, 1, 0, 0, 0, saoPaulo),
+				time.Date(2018, 11, 6, 0, 0, 0, 0, saoPaulo),
+			},
+			result: []string{"between 2018-11-03 and 2018-11-04", "2018-11-06"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dates := app.findUniqueDates(tc.input)
+			if len(dates) != len(tc.dates) {
+				t.Fatalf("Expected get %v, but got %v", tc.dates, dates)
+			}
+			for i, date := range dates {
+				if !date.Equal(tc.dates[i]) {
+					t.Errorf("Expected get %v, but got %v", tc.dates[i], date)
+				}
+			}
+
+			dateStrings := app.DateArrayConcatString(tc.input)
+			if len(dateStrings) != len(tc.result) {
+				t.Fatalf("Expected get %v, but got %v", tc.result, dateStrings)
+			}
+			for i, dateString := range dateStrings {
+				if dateString != tc.result[i] {
+					t.Errorf("Expected get %v, but got %v", tc.result, dateStrings)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// mesurement decoded during validation and the offset of it in the input
+type positionedMesurement struct {
+	types.Mesurement
+	offset int64
+}
+
+// function to validate every input against the expected schema and check data quality
+func (a Application) Validate() (types.ValidationSummary, error) {
+	rawReader, ok := a.reader.(reader.RawReader)
+	if !ok {
+		return types.ValidationSummary{}, errors.New("reader does not provide undecoded input for validation")
+	}
+
+	inputs, err := rawReader.GetRawInputs()
+	if err != nil {
+		return types.ValidationSummary{}, err
+	}
+
+	summary := types.ValidationSummary{
+		Valid: true,
+		Files: make([]types.FileValidation, 0, len(inputs)),
+	}
+	for _, input := range inputs {
+		result := a.validateInput(input)
+
+		summary.Files = append(summary.Files, result)
+		summary.Errors += len(result.Errors)
+		summary.Warnings += len(result.Warnings)
+		if !result.Valid {
+			summary.Valid = false
+		}
+	}
+
+	return summary, nil
+}
+
+// function to validate one input, schema problem is reported as error and data quality problem as warning
+func (a Application) validateInput(input types.RawInput) types.FileValidation {
+	content := input.Content
+	result := types.FileValidation{
+		Name:     input.Name,
+		Errors:   make([]types.ValidationIssue, 0),
+		Warnings: make([]types.ValidationIssue, 0),
+	}
+	issue := func(offset int64, field string, message string) types.ValidationIssue {
+		line, column := position(content, offset)
+		return types.ValidationIssue{Line: line, Column: column, Field: field, Message: message}
+	}
+	addError := func(offset int64, field string, message string) {
+		result.Errors = append(result.Errors, issue(offset, field, message))
+	}
+	addWarning := func(offset int64, field string, message string) {
+		result.Warnings = append(result.Warnings, issue(offset, field, message))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	token, err := decoder.Token()
+	if err != nil {
+		addError(errorOffset(err, decoder, content), "", syntaxMessage(err))
+		return result
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		addError(skipSeparator(content, 0), "", "expected array of mesurement")
+		return result
+	}
+
+	mesurements := make([]positionedMesurement, 0)
+	for decoder.More() {
+		offset := skipSeparator(content, decoder.InputOffset())
+
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err != nil {
+			addError(errorOffset(err, decoder, content), "", syntaxMessage(err))
+			return result
+		}
+
+		mesurement, ok := a.validateMesurement(raw, offset, addError, addWarning)
+		if ok {
+			mesurements = append(mesurements, positionedMesurement{Mesurement: mesurement, offset: offset})
+		}
+	}
+
+	// closing bracket
+	if _, err := decoder.Token(); err != nil {
+		addError(errorOffset(err, decoder, content), "", syntaxMessage(err))
+		return result
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		addError(skipSeparator(content, decoder.InputOffset()), "", "unexpected data after array")
+		return result
+	}
+
+	result.Mesurements = len(mesurements)
+	a.checkDataQuality(mesurements, addWarning)
+	result.Valid = len(result.Errors) == 0
+
+	return result
+}
+
+// function to validate fields of one mesurement object starting at offset of the input
+func (a Application) validateMesurement(raw json.RawMessage, offset int64, addError func(int64, string, string), addWarning func(int64, string, string)) (types.Mesurement, bool) {
+	if kind := jsonKind(raw); kind != "object" {
+		addError(offset, "", fmt.Sprintf("expected mesurement object, got %s", kind))
+		return types.Mesurement{}, false
+	}
+
+	var mesurement types.Mesurement
+	valid := true
+	seen := make(map[string]bool)
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// opening brace, syntax is already checked when decoding raw
+	decoder.Token()
+	for decoder.More() {
+		token, _ := decoder.Token()
+		key, _ := token.(string)
+		keyEnd := decoder.InputOffset()
+
+		var value json.RawMessage
+		decoder.Decode(&value)
+		valueOffset := offset + skipSeparator(raw, keyEnd)
+
+		switch key {
+		case "metricValue":
+			seen[key] = true
+			if kind := jsonKind(value); kind != "number" {
+				addError(valueOffset, key, fmt.Sprintf("expected number, got %s", kind))
+				valid = false
+				continue
+			}
+			json.Unmarshal(value, &mesurement.MetricValue)
+		case "dtime":
+			seen[key] = true
+			if kind := jsonKind(value); kind != "string" {
+				addError(valueOffset, key, fmt.Sprintf("expected date string, got %s", kind))
+				valid = false
+				continue
+			}
+			err := json.Unmarshal(value, &mesurement.Dtime)
+			if err != nil {
+				addError(valueOffset, key, fmt.Sprintf("invalid date %s (expected YYYY-MM-DD or RFC3339 timestamp)", value))
+				valid = false
+			}
+		default:
+			addWarning(offset+keyEnd-int64(len(key))-2, key, fmt.Sprintf("unknown field %q is ignored", key))
+		}
+	}
+
+	for _, key := range []string{"metricValue", "dtime"} {
+		if !seen[key] {
+			addError(offset, key, fmt.Sprintf("missing field %q", key))
+			valid = false
+		}
+	}
+
+	return mesurement, valid
+}
+
+// function to warn about value and timestamp that is schema-valid but suspicious
+func (a Application) checkDataQuality(mesurements []positionedMesurement, addWarning func(int64, string, string)) {
+	if len(mesurements) == 0 {
+		addWarning(0, "", "input has no mesurement")
+		return
+	}
+
+	seen := make(map[time.Time]bool)
+	outOfOrder := false
+	for i, mesurement := range mesurements {
+		if mesurement.MetricValue < 0 {
+			addWarning(mesurement.offset, "metricValue", "negative value")
+		} else if mesurement.MetricValue == 0 {
+			addWarning(mesurement.offset, "metricValue", "zero value (possible outage or missing data)")
+		}
+
+		if seen[mesurement.Dtime.Time] {
+			addWarning(mesurement.offset, "dtime", fmt.Sprintf("duplicate date %s", mesurement.Dtime.Format(time.RFC3339)))
+		}
+		seen[mesurement.Dtime.Time] = true
+
+		if !outOfOrder && i > 0 && mesurement.Dtime.Before(mesurements[i-1].Dtime.Time) {
+			addWarning(mesurement.offset, "dtime", "mesurement is not in chronological order")
+			outOfOrder = true
+		}
+	}
+
+	sorted := make([]positionedMesurement, 0, len(seen))
+	added := make(map[time.Time]bool)
+	for _, mesurement := range mesurements {
+		if !added[mesurement.Dtime.Time] {
+			added[mesurement.Dtime.Time] = true
+			sorted = append(sorted, mesurement)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Dtime.Before(sorted[j].Dtime.Time)
+	})
+	if len(sorted) < 3 {
+		return
+	}
+
+	// interval much longer than the usual sampling interval is reported as gap
+	intervals := make([]time.Duration, 0, len(sorted)-1)
+	for i := 1; i < len(sorted); i++ {
+		intervals = append(intervals, sorted[i].Dtime.Sub(sorted[i-1].Dtime.Time))
+	}
+	usual := make([]time.Duration, len(intervals))
+	copy(usual, intervals)
+	sort.Slice(usual, func(i, j int) bool {
+		return usual[i] < usual[j]
+	})
+	median := usual[(len(usual)-1)/2]
+
+	for i, interval := range intervals {
+		if interval*2 > median*3 {
+			addWarning(sorted[i+1].offset, "dtime", fmt.Sprintf("gap in mesurement between %s and %s", sorted[i].Dtime.Format(time.RFC3339), sorted[i+1].Dtime.Format(time.RFC3339)))
+		}
+	}
+}
+
+// function to convert byte offset of content into 1-based line and column
+func position(content []byte, offset int64) (int, int) {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+
+	before := content[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+
+	return line, column
+}
+
+// function to skip whitespace, comma and colon between JSON tokens
+func skipSeparator(content []byte, offset int64) int64 {
+	for offset < int64(len(content)) {
+		switch content[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+
+	return offset
+}
+
+// function to find offset of decoding error
+func errorOffset(err error, decoder *json.Decoder, content []byte) int64 {
+	var syntaxError *json.SyntaxError
+	// offset of syntax error is after reading the invalid character (or the end of input when it is truncated)
+	if errors.As(err, &syntaxError) && syntaxError.Offset > 0 && syntaxError.Offset < int64(len(content)) {
+		return syntaxError.Offset - 1
+	} else if syntaxError != nil {
+		return syntaxError.Offset
+	}
+
+	return decoder.InputOffset()
+}
+
+// function to describe decoding error
+func syntaxMessage(err error) string {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return "unexpected end of JSON input"
+	}
+
+	return err.Error()
+}
+
+// function to describe type of raw JSON value
+func jsonKind(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "nothing"
+	}
+
+	switch raw[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	}
+
+	return "number"
+}
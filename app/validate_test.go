@@ -0,0 +1,169 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestValidateInput(t *testing.T) {
+	type testcase struct {
+		name     string
+		input    string
+		valid    bool
+		errors   []types.ValidationIssue
+		warnings []types.ValidationIssue
+	}
+
+	testcases := []testcase{
+		{
+			name: "Valid",
+			input: `[
+  {"metricValue": 1, "dtime": "2021-01-01"},
+  {"metricValue": 2, "dtime": "2021-01-02"}
+]`,
+			valid:    true,
+			errors:   []types.ValidationIssue{},
+			warnings: []types.ValidationIssue{},
+		},
+		{
+			name: "Syntax error",
+			input: `[
+  {"metricValue": 1 "dtime": "2021-01-01"}
+]`,
+			errors: []types.ValidationIssue{
+				{Line: 2, Column: 21, Message: `invalid character '"' after object key:value pair`},
+			},
+			warnings: []types.ValidationIssue{},
+		},
+		{
+			name:  "Truncated",
+			input: `[{"metricValue": 1, "dtime": "2021-01-01"}`,
+			errors: []types.ValidationIssue{
+				{Line: 1, Column: 43, Message: "unexpected end of JSON input"},
+			},
+			warnings: []types.ValidationIssue{},
+		},
+		{
+			name:  "Not array",
+			input: `{"metricValue": 1, "dtime": "2021-01-01"}`,
+			errors: []types.ValidationIssue{
+				{Line: 1, Column: 1, Message: "expected array of mesurement"},
+			},
+			warnings: []types.ValidationIssue{},
+		},
+		{
+			name: "Type mismatch",
+			input: `[
+  {"metricValue": "1", "dtime": "2021-01-01"},
+  {"metricValue": 2, "dtime": "2021/01/02"},
+  {"metricValue": 3, "dtime": 20210103},
+  {"dtime": "2021-01-04"},
+  4
+]`,
+			errors: []types.ValidationIssue{
+				{Line: 2, Column: 19, Field: "metricValue", Message: "expected number, got string"},
+				{Line: 3, Column: 31, Field: "dtime", Message: `invalid date "2021/01/02" (expected YYYY-MM-DD or RFC3339 timestamp)`},
+				{Line: 4, Column: 31, Field: "dtime", Message: "expected date string, got number"},
+				{Line: 5, Column: 3, Field: "metricValue", Message: `missing field "metricValue"`},
+				{Line: 6, Column: 3, Message: "expected mesurement object, got number"},
+			},
+			warnings: []types.ValidationIssue{
+				{Line: 1, Column: 1, Message: "input has no mesurement"},
+			},
+		},
+		{
+			name: "Data quality",
+			input: `[
+  {"metricValue": 1, "dtime": "2021-01-01", "unit": "bps"},
+  {"metricValue": 0, "dtime": "2021-01-02"},
+  {"metricValue": -1, "dtime": "2021-01-02"},
+  {"metricValue": 1, "dtime": "2021-01-03"},
+  {"metricValue": 1, "dtime": "2021-01-06"},
+  {"metricValue": 1, "dtime": "2021-01-05"}
+]`,
+			valid:  true,
+			errors: []types.ValidationIssue{},
+			warnings: []types.ValidationIssue{
+				{Line: 2, Column: 45, Field: "unit", Message: `unknown field "unit" is ignored`},
+				{Line: 3, Column: 3, Field: "metricValue", Message: "zero value (possible outage or missing data)"},
+				{Line: 4, Column: 3, Field: "metricValue", Message: "negative value"},
+				{Line: 4, Column: 3, Field: "dtime", Message: "duplicate date 2021-01-02T00:00:00Z"},
+				{Line: 7, Column: 3, Field: "dtime", Message: "mesurement is not in chronological order"},
+				{Line: 7, Column: 3, Field: "dtime", Message: "gap in mesurement between 2021-01-03T00:00:00Z and 2021-01-05T00:00:00Z"},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := app.validateInput(types.RawInput{Name: "1.json", Content: []byte(tc.input)})
+			if result.Valid != tc.valid {
+				t.Errorf("Expected get %v, but got %v", tc.valid, result.Valid)
+			}
+			if len(result.Errors) != len(tc.errors) {
+				t.Fatalf("Expected get %v, but got %v", tc.errors, result.Errors)
+			}
+			for i, issue := range result.Errors {
+				if issue != tc.errors[i] {
+					t.Errorf("Expected get %v, but got %v", tc.errors[i], issue)
+				}
+			}
+			if len(result.Warnings) != len(tc.warnings) {
+				t.Fatalf("Expected get %v, but got %v", tc.warnings, result.Warnings)
+			}
+			for i, issue := range result.Warnings {
+				if issue != tc.warnings[i] {
+					t.Errorf("Expected get %v, but got %v", tc.warnings[i], issue)
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	application := NewApplication(mockRawReader{
+		inputs: []types.RawInput{
+			{Name: "1.json", Content: []byte(`[{"metricValue": 1, "dtime": "2021-01-01"}]`)},
+			{Name: "2.json", Content: []byte(`[{"metricValue": "1", "dtime": "2021-01-01"}]`)},
+		},
+	}, mockWriter{})
+
+	summary, err := application.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Valid {
+		t.Errorf("Expected get invalid summary, but got valid")
+	}
+	if len(summary.Files) != 2 || !summary.Files[0].Valid || summary.Files[1].Valid {
+		t.Errorf("Expected only 2.json invalid, but got %v", summary.Files)
+	}
+	if summary.Errors != 1 || summary.Warnings != 1 {
+		t.Errorf("Expected get 1 error and 1 warning, but got %d and %d", summary.Errors, summary.Warnings)
+	}
+
+	_, err = app.Validate()
+	if err == nil {
+		t.Errorf("Expected error for reader without raw input, but got nil")
+	}
+}
+
+type mockRawReader struct {
+	mockReader
+	inputs []types.RawInput
+}
+
+func (r mockRawReader) GetRawInputs() ([]types.RawInput, error) {
+	return r.inputs, nil
+}
+
+func (r mockRawReader) GetRawInput(name string) (types.RawInput, error) {
+	for _, input := range r.inputs {
+		if input.Name == name {
+			return input, nil
+		}
+	}
+
+	return types.RawInput{}, nil
+}
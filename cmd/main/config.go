@@ -0,0 +1,485 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/awcjack/samknows-backend-code-test/config"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/server"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/watcher"
+	"github.com/urfave/cli/v2"
+)
+
+// prefix of environment variable that override config file
+const envPrefix = "PERFORMANCE_ANALYSER_"
+
+func envVars(name string) []string {
+	return []string{envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))}
+}
+
+// flags that override config file, precedence is flag > environment variable > config file > default
+func configFlags() []cli.Flag {
+	defaults := config.Default()
+
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Usage:   "YAML config file",
+			EnvVars: envVars("config"),
+		},
+		&cli.StringFlag{
+			Name:    "input-dir",
+			Usage:   "directory that input files are read from",
+			Value:   defaults.Input.Dir,
+			EnvVars: envVars("input-dir"),
+		},
+		&cli.BoolFlag{
+			Name:    "recursive",
+			Usage:   "read input files in subdirectories as well (outputs mirror the input tree)",
+			EnvVars: envVars("recursive"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "include",
+			Usage:   "glob pattern that input file must match (e.g. 'site-*/**/*.json'), can be repeated",
+			EnvVars: envVars("include"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "exclude",
+			Usage:   "glob pattern of input files and directories that are skipped, can be repeated",
+			Value:   cli.NewStringSlice(defaults.Input.Exclude...),
+			EnvVars: envVars("exclude"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "extension",
+			Usage:   "extension of input files, can be repeated",
+			Value:   cli.NewStringSlice(defaults.Input.Extensions...),
+			EnvVars: envVars("extension"),
+		},
+		&cli.StringFlag{
+			Name:    "symlinks",
+			Usage:   "what to do with symbolic link under input directory (follow, skip or error)",
+			Value:   defaults.Input.Symlinks,
+			EnvVars: envVars("symlinks"),
+		},
+		&cli.Int64Flag{
+			Name:    "max-file-size",
+			Usage:   "maximum size in bytes of one input file or archive entry after decompression (0 means unlimited)",
+			Value:   defaults.Input.MaxFileSize,
+			EnvVars: envVars("max-file-size"),
+		},
+		&cli.StringFlag{
+			Name:    "output-dir",
+			Usage:   "directory that reports are written to",
+			Value:   defaults.Output.Dir,
+			EnvVars: envVars("output-dir"),
+		},
+		&cli.StringFlag{
+			Name:    "input-sqlite",
+			Usage:   "SQLite database that mesurements are read from instead of input directory",
+			EnvVars: envVars("input-sqlite"),
+		},
+		&cli.StringFlag{
+			Name:    "input-postgres",
+			Usage:   "PostgreSQL (or TimescaleDB) connection string that mesurements are read from instead of input directory",
+			EnvVars: envVars("input-postgres"),
+		},
+		&cli.StringFlag{
+			Name:    "postgres-table",
+			Usage:   "table that {{.Table}} in postgres query templates is replaced with",
+			Value:   defaults.Input.Postgres.Table,
+			EnvVars: envVars("postgres-table"),
+		},
+		&cli.StringFlag{
+			Name:    "postgres-devices-query",
+			Usage:   "query template of devices in range ($1 = from, $2 = to)",
+			Value:   defaults.Input.Postgres.DevicesQuery,
+			EnvVars: envVars("postgres-devices-query"),
+		},
+		&cli.StringFlag{
+			Name:    "postgres-query",
+			Usage:   "query template of (dtime, metric_value) rows of one device ($1 = device, $2 = from, $3 = to)",
+			Value:   defaults.Input.Postgres.MesurementsQuery,
+			EnvVars: envVars("postgres-query"),
+		},
+		&cli.StringFlag{
+			Name:    "postgres-from",
+			Usage:   "read mesurement from this date (YYYY-MM-DD in --timezone or RFC3339, inclusive)",
+			EnvVars: envVars("postgres-from"),
+		},
+		&cli.StringFlag{
+			Name:    "postgres-to",
+			Usage:   "read mesurement until this date (YYYY-MM-DD in --timezone or RFC3339, exclusive)",
+			EnvVars: envVars("postgres-to"),
+		},
+		&cli.IntFlag{
+			Name:    "postgres-max-mesurements",
+			Usage:   "maximum number of rows read for one device (0 means unlimited)",
+			Value:   defaults.Input.Postgres.MaxMesurements,
+			EnvVars: envVars("postgres-max-mesurements"),
+		},
+		&cli.StringFlag{
+			Name:    "input-lineprotocol",
+			Usage:   "directory of InfluxDB line protocol files that mesurements are read from instead of input directory",
+			EnvVars: envVars("input-lineprotocol"),
+		},
+		&cli.StringFlag{
+			Name:    "lineprotocol-measurement",
+			Usage:   "measurement of line protocol points that are read",
+			Value:   defaults.Input.LineProtocol.Measurement,
+			EnvVars: envVars("lineprotocol-measurement"),
+		},
+		&cli.StringFlag{
+			Name:    "lineprotocol-field",
+			Usage:   "field of line protocol point holding metric value in bytes per second",
+			Value:   defaults.Input.LineProtocol.Field,
+			EnvVars: envVars("lineprotocol-field"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "lineprotocol-device-tag",
+			Usage:   "tag whose value form the device key of line protocol point, can be repeated",
+			Value:   cli.NewStringSlice(defaults.Input.LineProtocol.DeviceTags...),
+			EnvVars: envVars("lineprotocol-device-tag"),
+		},
+		&cli.StringFlag{
+			Name:    "lineprotocol-precision",
+			Usage:   "unit of line protocol timestamp (ns, us, ms or s)",
+			Value:   defaults.Input.LineProtocol.Precision,
+			EnvVars: envVars("lineprotocol-precision"),
+		},
+		&cli.StringFlag{
+			Name:    "output-sqlite",
+			Usage:   "SQLite database that reports and results are written to instead of output directory",
+			EnvVars: envVars("output-sqlite"),
+		},
+		&cli.StringFlag{
+			Name:    "metrics-file",
+			Usage:   "OpenMetrics file that per-device statistics are written to (e.g. for node_exporter textfile collector)",
+			EnvVars: envVars("metrics-file"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "format",
+			Usage:   "report format (text, json, html, markdown or lineprotocol), can be repeated",
+			Value:   cli.NewStringSlice(defaults.Output.Formats...),
+			EnvVars: envVars("format"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "template",
+			Usage:   "template file replacing default layout in format=path format (e.g. text=report.tmpl), can be repeated",
+			EnvVars: envVars("template"),
+		},
+		&cli.StringFlag{
+			Name:    "output-name",
+			Usage:   "template of output name (fields: Name, Dir, Base, Path, Ext, Format, Extension)",
+			Value:   defaults.Output.Naming.Template,
+			EnvVars: envVars("output-name"),
+		},
+		&cli.StringFlag{
+			Name:    "on-collision",
+			Usage:   "what to do when two reports get the same output name (error or suffix)",
+			Value:   defaults.Output.Naming.Collision,
+			EnvVars: envVars("on-collision"),
+		},
+		&cli.StringFlag{
+			Name:    "overwrite",
+			Usage:   "what to do when output already exists (overwrite, skip or fail)",
+			Value:   defaults.Output.Naming.Overwrite,
+			EnvVars: envVars("overwrite"),
+		},
+		&cli.BoolFlag{
+			Name:    "fsync",
+			Usage:   "flush every output file to disk before it replace the previous one",
+			EnvVars: envVars("fsync"),
+		},
+		&cli.StringFlag{
+			Name:    "timezone",
+			Usage:   "IANA timezone that dates are reported and bucketed in",
+			Value:   defaults.Timezone,
+			EnvVars: envVars("timezone"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "input-timezone",
+			Usage:   "IANA timezone for one input in name=zone format (e.g. device1.json=Europe/London), can be repeated",
+			EnvVars: envVars("input-timezone"),
+		},
+		&cli.StringSliceFlag{
+			Name:    "aggregate",
+			Usage:   "add statistics table per calendar bucket (week, month or weekday), can be repeated",
+			EnvVars: envVars("aggregate"),
+		},
+		&cli.BoolFlag{
+			Name:    "peak",
+			Usage:   "compare performance in peak hours against off-peak hours (requires mesurement with time of day)",
+			EnvVars: envVars("peak"),
+		},
+		&cli.StringFlag{
+			Name:    "peak-hours",
+			Usage:   "daily peak hours window in HH:MM-HH:MM format",
+			Value:   defaults.Peak.Hours,
+			EnvVars: envVars("peak-hours"),
+		},
+		&cli.StringFlag{
+			Name:    "peak-timezone",
+			Usage:   "IANA timezone that peak hours window is in (default to the timezone input is analysed in)",
+			EnvVars: envVars("peak-timezone"),
+		},
+		&cli.Float64Flag{
+			Name:    "peak-threshold",
+			Usage:   "flag peak hours as under-performing when peak average is below this fraction of off-peak average",
+			Value:   defaults.Peak.Threshold,
+			EnvVars: envVars("peak-threshold"),
+		},
+		&cli.StringFlag{
+			Name:    "outlier-method",
+			Usage:   "rule to find under-performing mesurement (iqr or zscore)",
+			Value:   defaults.Outlier.Method,
+			EnvVars: envVars("outlier-method"),
+		},
+		&cli.Float64Flag{
+			Name:    "outlier-multiplier",
+			Usage:   "multiplier of IQR (iqr) or standard deviation (zscore) below which mesurement is under-performing",
+			Value:   defaults.Outlier.Multiplier,
+			EnvVars: envVars("outlier-multiplier"),
+		},
+		&cli.StringFlag{
+			Name:    "unit",
+			Usage:   "unit that values are reported in (auto, bps, kbps, mbps, gbps, tbps or pbps)",
+			Value:   defaults.Unit,
+			EnvVars: envVars("unit"),
+		},
+	}
+}
+
+// flags of analysing input directory once
+func runFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "cache",
+			Usage:   "file caching hash of analysed inputs and options, unchanged inputs are skipped (default to .cache.json in output directory)",
+			EnvVars: envVars("cache"),
+		},
+		&cli.BoolFlag{
+			Name:    "force",
+			Usage:   "analyse every input and rewrite every report even if cached",
+			EnvVars: envVars("force"),
+		},
+	}
+}
+
+// flags of serve subcommand
+func serveFlags() []cli.Flag {
+	defaults := server.DefaultOptions()
+
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "listen",
+			Usage:   "address that HTTP server listen on",
+			Value:   ":8080",
+			EnvVars: envVars("listen"),
+		},
+		&cli.Int64Flag{
+			Name:    "max-body-bytes",
+			Usage:   "maximum size of request body",
+			Value:   defaults.MaxBodyBytes,
+			EnvVars: envVars("max-body-bytes"),
+		},
+		&cli.DurationFlag{
+			Name:    "read-timeout",
+			Usage:   "maximum duration for reading the request",
+			Value:   defaults.ReadTimeout,
+			EnvVars: envVars("read-timeout"),
+		},
+		&cli.DurationFlag{
+			Name:    "write-timeout",
+			Usage:   "maximum duration before timing out writing the response",
+			Value:   defaults.WriteTimeout,
+			EnvVars: envVars("write-timeout"),
+		},
+		&cli.DurationFlag{
+			Name:    "handler-timeout",
+			Usage:   "maximum duration for analysing one request",
+			Value:   defaults.HandlerTimeout,
+			EnvVars: envVars("handler-timeout"),
+		},
+		&cli.DurationFlag{
+			Name:    "shutdown-timeout",
+			Usage:   "maximum duration to wait for in-flight requests on shutdown",
+			Value:   defaults.ShutdownTimeout,
+			EnvVars: envVars("shutdown-timeout"),
+		},
+		&cli.IntFlag{
+			Name:    "max-devices",
+			Usage:   "maximum number of devices exposed on /metrics, device posted least recently is dropped first (0 means unlimited)",
+			Value:   defaults.MaxDevices,
+			EnvVars: envVars("max-devices"),
+		},
+	}
+}
+
+// flags of watch subcommand
+func watchFlags() []cli.Flag {
+	defaults := watcher.DefaultOptions()
+
+	return []cli.Flag{
+		&cli.DurationFlag{
+			Name:    "debounce",
+			Usage:   "quiet period after last write before file is analysed",
+			Value:   defaults.Debounce,
+			EnvVars: envVars("debounce"),
+		},
+		&cli.StringFlag{
+			Name:    "state",
+			Usage:   "file recording hash of processed inputs (default to .processed.json in output directory)",
+			EnvVars: envVars("state"),
+		},
+	}
+}
+
+// function to load config file (if provided) then apply flags and environment variables that are set
+func loadConfig(cCtx *cli.Context) (config.Config, error) {
+	cfg := config.Default()
+	if path := cCtx.String("config"); path != "" {
+		var err error
+		cfg, err = config.Load(path)
+		if err != nil {
+			return config.Config{}, err
+		}
+	}
+
+	if cCtx.IsSet("input-dir") {
+		cfg.Input.Dir = cCtx.String("input-dir")
+	}
+	if cCtx.IsSet("recursive") {
+		cfg.Input.Recursive = cCtx.Bool("recursive")
+	}
+	if cCtx.IsSet("include") {
+		cfg.Input.Include = cCtx.StringSlice("include")
+	}
+	if cCtx.IsSet("exclude") {
+		cfg.Input.Exclude = cCtx.StringSlice("exclude")
+	}
+	if cCtx.IsSet("extension") {
+		cfg.Input.Extensions = cCtx.StringSlice("extension")
+	}
+	if cCtx.IsSet("symlinks") {
+		cfg.Input.Symlinks = cCtx.String("symlinks")
+	}
+	if cCtx.IsSet("max-file-size") {
+		cfg.Input.MaxFileSize = cCtx.Int64("max-file-size")
+	}
+	if cCtx.IsSet("output-dir") {
+		cfg.Output.Dir = cCtx.String("output-dir")
+	}
+	if cCtx.IsSet("input-sqlite") {
+		cfg.Input.SQLite = cCtx.String("input-sqlite")
+	}
+	if cCtx.IsSet("input-postgres") {
+		cfg.Input.Postgres.DSN = cCtx.String("input-postgres")
+	}
+	if cCtx.IsSet("postgres-table") {
+		cfg.Input.Postgres.Table = cCtx.String("postgres-table")
+	}
+	if cCtx.IsSet("postgres-devices-query") {
+		cfg.Input.Postgres.DevicesQuery = cCtx.String("postgres-devices-query")
+	}
+	if cCtx.IsSet("postgres-query") {
+		cfg.Input.Postgres.MesurementsQuery = cCtx.String("postgres-query")
+	}
+	if cCtx.IsSet("postgres-from") {
+		cfg.Input.Postgres.From = cCtx.String("postgres-from")
+	}
+	if cCtx.IsSet("postgres-to") {
+		cfg.Input.Postgres.To = cCtx.String("postgres-to")
+	}
+	if cCtx.IsSet("postgres-max-mesurements") {
+		cfg.Input.Postgres.MaxMesurements = cCtx.Int("postgres-max-mesurements")
+	}
+	if cCtx.IsSet("input-lineprotocol") {
+		cfg.Input.LineProtocol.Dir = cCtx.String("input-lineprotocol")
+	}
+	if cCtx.IsSet("lineprotocol-measurement") {
+		cfg.Input.LineProtocol.Measurement = cCtx.String("lineprotocol-measurement")
+	}
+	if cCtx.IsSet("lineprotocol-field") {
+		cfg.Input.LineProtocol.Field = cCtx.String("lineprotocol-field")
+	}
+	if cCtx.IsSet("lineprotocol-device-tag") {
+		cfg.Input.LineProtocol.DeviceTags = cCtx.StringSlice("lineprotocol-device-tag")
+	}
+	if cCtx.IsSet("lineprotocol-precision") {
+		cfg.Input.LineProtocol.Precision = cCtx.String("lineprotocol-precision")
+	}
+	if cCtx.IsSet("output-sqlite") {
+		cfg.Output.SQLite = cCtx.String("output-sqlite")
+	}
+	if cCtx.IsSet("metrics-file") {
+		cfg.Output.MetricsFile = cCtx.String("metrics-file")
+	}
+	if cCtx.IsSet("format") {
+		cfg.Output.Formats = cCtx.StringSlice("format")
+	}
+	if cCtx.IsSet("template") {
+		if cfg.Output.Templates == nil {
+			cfg.Output.Templates = make(map[string]string)
+		}
+		for _, value := range cCtx.StringSlice("template") {
+			format, path, ok := strings.Cut(value, "=")
+			if !ok {
+				return config.Config{}, fmt.Errorf("invalid template %q (expected format=path)", value)
+			}
+			cfg.Output.Templates[format] = path
+		}
+	}
+	if cCtx.IsSet("output-name") {
+		cfg.Output.Naming.Template = cCtx.String("output-name")
+	}
+	if cCtx.IsSet("on-collision") {
+		cfg.Output.Naming.Collision = cCtx.String("on-collision")
+	}
+	if cCtx.IsSet("overwrite") {
+		cfg.Output.Naming.Overwrite = cCtx.String("overwrite")
+	}
+	if cCtx.IsSet("fsync") {
+		cfg.Output.Sync = cCtx.Bool("fsync")
+	}
+	if cCtx.IsSet("timezone") {
+		cfg.Timezone = cCtx.String("timezone")
+	}
+	if cCtx.IsSet("input-timezone") {
+		if cfg.InputTimezones == nil {
+			cfg.InputTimezones = make(map[string]string)
+		}
+		for _, value := range cCtx.StringSlice("input-timezone") {
+			name, zone, ok := strings.Cut(value, "=")
+			if !ok {
+				return config.Config{}, fmt.Errorf("invalid input timezone %q (expected name=zone)", value)
+			}
+			cfg.InputTimezones[name] = zone
+		}
+	}
+	if cCtx.IsSet("aggregate") {
+		cfg.Aggregations = cCtx.StringSlice("aggregate")
+	}
+	if cCtx.IsSet("peak") {
+		cfg.Peak.Enabled = cCtx.Bool("peak")
+	}
+	if cCtx.IsSet("peak-hours") {
+		cfg.Peak.Hours = cCtx.String("peak-hours")
+	}
+	if cCtx.IsSet("peak-timezone") {
+		cfg.Peak.Timezone = cCtx.String("peak-timezone")
+	}
+	if cCtx.IsSet("peak-threshold") {
+		cfg.Peak.Threshold = cCtx.Float64("peak-threshold")
+	}
+	if cCtx.IsSet("outlier-method") {
+		cfg.Outlier.Method = cCtx.String("outlier-method")
+	}
+	if cCtx.IsSet("outlier-multiplier") {
+		cfg.Outlier.Multiplier = cCtx.Float64("outlier-multiplier")
+	}
+	if cCtx.IsSet("unit") {
+		cfg.Unit = cCtx.String("unit")
+	}
+
+	return cfg, nil
+}
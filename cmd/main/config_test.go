@@ -0,0 +1,187 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/awcjack/samknows-backend-code-test/config"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
+	"github.com/urfave/cli/v2"
+)
+
+// function to load config the way the root command does from command line arguments
+func loadConfigFromArgs(t *testing.T, args []string) config.Config {
+	t.Helper()
+
+	var cfg config.Config
+	cliApp := &cli.App{
+		Flags: append(configFlags(), runFlags()...),
+		Action: func(cCtx *cli.Context) error {
+			var err error
+			cfg, err = loadConfig(cCtx)
+			return err
+		},
+	}
+
+	err := cliApp.Run(append([]string{"performance-analyser"}, args...))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg
+}
+
+func TestLoadConfig(t *testing.T) {
+	type testcase struct {
+		name       string
+		file       string
+		env        map[string]string
+		args       []string
+		inputDir   string
+		formats    []string
+		recursive  bool
+		metricFile string
+	}
+
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(file, []byte(`input:
+  dir: from-file
+  recursive: true
+output:
+  formats: [json]
+  metricsFile: file.prom
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Default",
+			inputDir: config.Default().Input.Dir,
+			formats:  config.Default().Output.Formats,
+		},
+		{
+			name:       "Config file",
+			file:       file,
+			inputDir:   "from-file",
+			formats:    []string{"json"},
+			recursive:  true,
+			metricFile: "file.prom",
+		},
+		{
+			name:       "Environment over config file",
+			file:       file,
+			env:        map[string]string{"PERFORMANCE_ANALYSER_INPUT_DIR": "from-env", "PERFORMANCE_ANALYSER_FORMAT": "html,markdown"},
+			inputDir:   "from-env",
+			formats:    []string{"html", "markdown"},
+			recursive:  true,
+			metricFile: "file.prom",
+		},
+		{
+			name:       "Flag over environment",
+			file:       file,
+			env:        map[string]string{"PERFORMANCE_ANALYSER_INPUT_DIR": "from-env", "PERFORMANCE_ANALYSER_RECURSIVE": "true"},
+			args:       []string{"--input-dir", "from-flag", "--recursive=false", "--format", "text"},
+			inputDir:   "from-flag",
+			formats:    []string{"text"},
+			recursive:  false,
+			metricFile: "file.prom",
+		},
+		{
+			name:       "Config file from environment",
+			env:        map[string]string{"PERFORMANCE_ANALYSER_CONFIG": file, "PERFORMANCE_ANALYSER_METRICS_FILE": "env.prom"},
+			inputDir:   "from-file",
+			formats:    []string{"json"},
+			recursive:  true,
+			metricFile: "env.prom",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.env {
+				t.Setenv(key, value)
+			}
+			args := tc.args
+			if tc.file != "" {
+				args = append([]string{"--config", tc.file}, args...)
+			}
+
+			cfg := loadConfigFromArgs(t, args)
+			if cfg.Input.Dir != tc.inputDir {
+				t.Errorf("Expected get %v, but got %v", tc.inputDir, cfg.Input.Dir)
+			}
+			if !reflect.DeepEqual(cfg.Output.Formats, tc.formats) {
+				t.Errorf("Expected get %v, but got %v", tc.formats, cfg.Output.Formats)
+			}
+			if cfg.Input.Recursive != tc.recursive {
+				t.Errorf("Expected get %v, but got %v", tc.recursive, cfg.Input.Recursive)
+			}
+			if cfg.Output.MetricsFile != tc.metricFile {
+				t.Errorf("Expected get %v, but got %v", tc.metricFile, cfg.Output.MetricsFile)
+			}
+		})
+	}
+}
+
+func TestOpenStorage(t *testing.T) {
+	type testcase struct {
+		name     string
+		cfg      func(cfg config.Config) config.Config
+		fileRead bool
+		err      bool
+	}
+
+	testcases := []testcase{
+		{
+			name: "Input directory",
+			cfg: func(cfg config.Config) config.Config {
+				return cfg
+			},
+			fileRead: true,
+		},
+		{
+			name: "SQLite",
+			cfg: func(cfg config.Config) config.Config {
+				cfg.Input.SQLite = filepath.Join(t.TempDir(), "input.db")
+				return cfg
+			},
+			fileRead: false,
+		},
+		{
+			name: "Two sources",
+			cfg: func(cfg config.Config) config.Config {
+				cfg.Input.SQLite = filepath.Join(t.TempDir(), "input.db")
+				cfg.Input.LineProtocol.Dir = t.TempDir()
+				return cfg
+			},
+			err: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Input.Dir = t.TempDir()
+			cfg.Output.Dir = t.TempDir()
+
+			r, _, closeStorage, err := openStorage(tc.cfg(cfg))
+			if tc.err != (err != nil) {
+				t.Fatalf("Expected get error %v, but got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			defer closeStorage()
+
+			// watch can only be used with reader of files
+			_, ok := r.(reader.FileReader)
+			if ok != tc.fileRead {
+				t.Errorf("Expected get %v, but got %v", tc.fileRead, ok)
+			}
+		})
+	}
+}
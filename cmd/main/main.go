@@ -1,28 +1,282 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"net"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
 
 	"github.com/awcjack/samknows-backend-code-test/app"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/cache"
 	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
-	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/rpc"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/server"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/watcher"
 	"github.com/urfave/cli/v2"
 )
 
 func main() {
-	// declare io reader and io writer that access filesystem
-	ioReader := reader.NewIOReader()
-	ioWriter := writer.NewIOWriter()
-	// declare application that use io reader and io writer
-	app := app.NewApplication(ioReader, ioWriter)
-
 	cliApp := &cli.App{
 		Name:      "performance-analyser",
 		Usage:     "application that analyse the download performance and find the under-performing period",
-		UsageText: "performance-analyser",
-		Action: func(*cli.Context) error {
-			err := app.Run()
+		UsageText: "performance-analyser [--config file] [options]",
+		Flags:     append(configFlags(), runFlags()...),
+		Commands: []*cli.Command{
+			{
+				Name:      "serve",
+				Usage:     "serve HTTP API that analyse posted mesurement series (POST /analyse)",
+				UsageText: "performance-analyser [--config file] [options] serve [--listen addr]",
+				Flags:     serveFlags(),
+				Action: func(cCtx *cli.Context) error {
+					cfg, err := loadConfig(cCtx)
+					if err != nil {
+						return err
+					}
+					analysis, err := cfg.Analysis()
+					if err != nil {
+						return err
+					}
+
+					inputReader, outputWriter, closeStorage, err := openStorage(cfg)
+					if err != nil {
+						return err
+					}
+					defer closeStorage()
+
+					app := app.NewApplicationWithConfig(inputReader, outputWriter, analysis)
+					options := server.Options{
+						MaxBodyBytes:    cCtx.Int64("max-body-bytes"),
+						ReadTimeout:     cCtx.Duration("read-timeout"),
+						WriteTimeout:    cCtx.Duration("write-timeout"),
+						HandlerTimeout:  cCtx.Duration("handler-timeout"),
+						ShutdownTimeout: cCtx.Duration("shutdown-timeout"),
+						MaxDevices:      cCtx.Int("max-devices"),
+					}
+
+					// shutdown gracefully on interrupt
+					ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt, syscall.SIGTERM)
+					defer stop()
+
+					log.Printf("listening on %s", cCtx.String("listen"))
+					return server.NewServer(app, options).ListenAndServe(ctx, cCtx.String("listen"))
+				},
+			},
+			{
+				Name:      "serve-grpc",
+				Usage:     "serve gRPC AnalyserService (see infrastructure/rpc/analyserpb/analyser.proto)",
+				UsageText: "performance-analyser [--config file] [options] serve-grpc [--listen addr]",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "listen",
+						Usage:   "address that gRPC server listen on",
+						Value:   ":9090",
+						EnvVars: envVars("grpc-listen"),
+					},
+					&cli.IntFlag{
+						Name:    "max-recv-msg-size",
+						Usage:   "maximum size of one request message in bytes",
+						Value:   rpc.DefaultOptions().MaxRecvMsgSize,
+						EnvVars: envVars("grpc-max-recv-msg-size"),
+					},
+					&cli.IntFlag{
+						Name:    "max-measurements",
+						Usage:   "maximum number of measurements of one series, including every message of a stream (0 means unlimited)",
+						Value:   rpc.DefaultOptions().MaxMeasurements,
+						EnvVars: envVars("grpc-max-measurements"),
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					cfg, err := loadConfig(cCtx)
+					if err != nil {
+						return err
+					}
+					analysis, err := cfg.Analysis()
+					if err != nil {
+						return err
+					}
+
+					inputReader, outputWriter, closeStorage, err := openStorage(cfg)
+					if err != nil {
+						return err
+					}
+					defer closeStorage()
+
+					listener, err := net.Listen("tcp", cCtx.String("listen"))
+					if err != nil {
+						return err
+					}
+
+					// stop gracefully on interrupt
+					ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt, syscall.SIGTERM)
+					defer stop()
+
+					app := app.NewApplicationWithConfig(inputReader, outputWriter, analysis)
+					log.Printf("listening on %s", listener.Addr())
+					options := rpc.Options{
+						MaxRecvMsgSize:  cCtx.Int("max-recv-msg-size"),
+						MaxMeasurements: cCtx.Int("max-measurements"),
+					}
+					return rpc.NewServer(app, options).Serve(ctx, listener)
+				},
+			},
+			{
+				Name:      "watch",
+				Usage:     "analyse new or modified input files as they are written",
+				UsageText: "performance-analyser [--config file] [options] watch [--debounce duration] [--state file]",
+				Flags:     watchFlags(),
+				Action: func(cCtx *cli.Context) error {
+					cfg, err := loadConfig(cCtx)
+					if err != nil {
+						return err
+					}
+					analysis, err := cfg.Analysis()
+					if err != nil {
+						return err
+					}
+
+					statePath := cCtx.String("state")
+					if statePath == "" {
+						statePath = filepath.Join(cfg.Output.Dir, ".processed.json")
+					}
+					record, err := cache.NewFileCache(statePath)
+					if err != nil {
+						return err
+					}
+
+					// stop watching on interrupt
+					ctx, stop := signal.NotifyContext(cCtx.Context, os.Interrupt, syscall.SIGTERM)
+					defer stop()
+
+					inputReader, outputWriter, closeStorage, err := openStorage(cfg)
+					if err != nil {
+						return err
+					}
+					defer closeStorage()
+					fileReader, ok := inputReader.(reader.FileReader)
+					if !ok {
+						return fmt.Errorf("watch only support input directory (input.dir), not database or line protocol input")
+					}
+
+					// every file is a separate run, so names written for earlier files are remembered to find collision between them
+					app := app.NewApplicationWithConfig(inputReader, outputWriter, analysis).WithOutputTracking()
+					options := watcher.Options{
+						Debounce:    cCtx.Duration("debounce"),
+						Recursive:   cfg.Input.Recursive,
+						Selected:    fileReader.Selected,
+						MaxFileSize: cfg.Input.MaxFileSize,
+					}
+					// file is decoded from the content that is recorded as processed, archive is expanded into its entries
+					process := func(name string, content []byte) error {
+						inputs, err := fileReader.DecodeFile(name, content)
+						if err != nil {
+							return err
+						}
+						return app.RunInputs(inputs)
+					}
+
+					log.Printf("watching %s", cfg.Input.Dir)
+					return watcher.NewWatcher(cfg.Input.Dir, process, record, options).Run(ctx)
+				},
+			},
+			{
+				Name:      "template",
+				Usage:     "print default template of format (text, html or markdown) as a starting point of custom template",
+				UsageText: "performance-analyser template format",
+				Action: func(cCtx *cli.Context) error {
+					format, err := app.ParseFormat(cCtx.Args().First())
+					if err != nil {
+						return err
+					}
+					source, ok := app.DefaultTemplate(format)
+					if !ok {
+						return fmt.Errorf("format %s does not support template", format)
+					}
+
+					_, err = fmt.Fprint(cCtx.App.Writer, source)
+					return err
+				},
+			},
+			{
+				Name:      "validate",
+				Usage:     "check every input against the expected schema and report data quality warnings as JSON",
+				UsageText: "performance-analyser [--config file] [options] validate [--strict]",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "strict",
+						Usage: "exit non-zero when there is warning",
+					},
+				},
+				Action: func(cCtx *cli.Context) error {
+					cfg, err := loadConfig(cCtx)
+					if err != nil {
+						return err
+					}
+
+					inputReader, outputWriter, closeStorage, err := openStorage(cfg)
+					if err != nil {
+						return err
+					}
+					defer closeStorage()
+
+					app := app.NewApplication(inputReader, outputWriter)
+					summary, err := app.Validate()
+					if err != nil {
+						return err
+					}
+
+					encoder := json.NewEncoder(cCtx.App.Writer)
+					encoder.SetIndent("", "  ")
+					err = encoder.Encode(summary)
+					if err != nil {
+						return err
+					}
+
+					if !summary.Valid || (cCtx.Bool("strict") && summary.Warnings > 0) {
+						return cli.Exit("", 1)
+					}
+					return nil
+				},
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			cfg, err := loadConfig(cCtx)
+			if err != nil {
+				return err
+			}
+			analysis, err := cfg.Analysis()
+			if err != nil {
+				return err
+			}
+
+			// declare reader and writer that access filesystem or database
+			inputReader, outputWriter, closeStorage, err := openStorage(cfg)
+			if err != nil {
+				return err
+			}
+			defer closeStorage()
+
+			cachePath := cCtx.String("cache")
+			if cachePath == "" && cfg.Output.SQLite != "" {
+				cachePath = cfg.Output.SQLite + ".cache.json"
+			} else if cachePath == "" {
+				cachePath = filepath.Join(cfg.Output.Dir, ".cache.json")
+			}
+			inputCache, err := cache.NewFileCache(cachePath)
+			if err != nil {
+				return err
+			}
+
+			// declare application that use the reader and writer
+			app := app.NewApplicationWithConfig(inputReader, outputWriter, analysis)
+			// metrics file is rebuilt from every input, so no input can be skipped
+			if cfg.Output.MetricsFile == "" {
+				app = app.WithCache(inputCache, cCtx.Bool("force"))
+			}
+			err = app.Run()
 			if err != nil {
 				return err
 			}
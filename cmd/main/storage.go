@@ -0,0 +1,96 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/awcjack/samknows-backend-code-test/config"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/postgres"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/sqlite"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/writer"
+)
+
+// function to make reader and writer selected by config (database when set, directory otherwise), close release the databases
+func openStorage(cfg config.Config) (reader.Reader, writer.Writer, func(), error) {
+	databases := make([]*sql.DB, 0)
+	closeStorage := func() {
+		for _, db := range databases {
+			db.Close()
+		}
+	}
+	// input and output can share one database file
+	open := func(path string) (*sql.DB, error) {
+		if path == cfg.Input.SQLite && len(databases) > 0 {
+			return databases[0], nil
+		}
+		db, err := sqlite.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		databases = append(databases, db)
+		return db, nil
+	}
+
+	sources := 0
+	for _, source := range []string{cfg.Input.SQLite, cfg.Input.Postgres.DSN, cfg.Input.LineProtocol.Dir} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, nil, nil, fmt.Errorf("only one of input.sqlite, input.postgres.dsn and input.lineProtocol.dir can be used")
+	}
+
+	ioOptions, err := cfg.ReaderOptions()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var r reader.Reader = reader.NewIOReaderWithOptions(cfg.Input.Dir, ioOptions)
+	if cfg.Input.LineProtocol.Dir != "" {
+		options, err := cfg.LineProtocolOptions()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		r = reader.NewLineProtocolReader(cfg.Input.LineProtocol.Dir, options)
+	}
+	if cfg.Input.Postgres.DSN != "" {
+		options, err := cfg.PostgresOptions()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		db, err := postgres.Open(cfg.Input.Postgres.DSN)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		databases = append(databases, db)
+		r, err = reader.NewPostgresReader(db, options)
+		if err != nil {
+			closeStorage()
+			return nil, nil, nil, err
+		}
+	}
+	if cfg.Input.SQLite != "" {
+		db, err := open(cfg.Input.SQLite)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		r = reader.NewSQLiteReader(db)
+	}
+
+	var w writer.Writer = writer.NewIOWriterWithOptions(cfg.Output.Dir, cfg.WriterOptions())
+	if cfg.Output.SQLite != "" {
+		db, err := open(cfg.Output.SQLite)
+		if err != nil {
+			closeStorage()
+			return nil, nil, nil, err
+		}
+		w = writer.NewSQLiteWriter(db)
+	}
+
+	if cfg.Output.MetricsFile != "" {
+		w = writer.NewOpenMetricsWriter(w, cfg.Output.MetricsFile)
+	}
+
+	return r, w, closeStorage, nil
+}
@@ -0,0 +1,376 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/app"
+)
+
+func TestLoad(t *testing.T) {
+	type testcase struct {
+		name    string
+		content string
+		err     bool
+		check   func(t *testing.T, config Config)
+	}
+
+	testcases := []testcase{
+		{
+			name:    "Empty",
+			content: "",
+			check: func(t *testing.T, config Config) {
+				if config.Input.Dir != Default().Input.Dir {
+					t.Errorf("Expected get %v, but got %v", Default().Input.Dir, config.Input.Dir)
+				}
+			},
+		},
+		{
+			name: "Override",
+			content: `
+input:
+  dir: /data/in
+output:
+  sqlite: /data/analyser.db
+outlier:
+  method: zscore
+peak:
+  enabled: true
+`,
+			check: func(t *testing.T, config Config) {
+				if config.Input.Dir != "/data/in" {
+					t.Errorf("Expected get %v, but got %v", "/data/in", config.Input.Dir)
+				}
+				if config.Output.Dir != Default().Output.Dir {
+					t.Errorf("Expected get %v, but got %v", Default().Output.Dir, config.Output.Dir)
+				}
+				if config.Output.SQLite != "/data/analyser.db" || config.Input.SQLite != "" {
+					t.Errorf("Expected get output database only, but got %v and %v", config.Input.SQLite, config.Output.SQLite)
+				}
+				if config.Outlier.Method != "zscore" || config.Outlier.Multiplier != 1.5 {
+					t.Errorf("Expected get zscore with default multiplier, but got %v", config.Outlier)
+				}
+				if !config.Peak.Enabled || config.Peak.Hours != "20:00-22:00" {
+					t.Errorf("Expected get enabled default peak hours, but got %v", config.Peak)
+				}
+			},
+		},
+		{
+			name:    "Unknown key",
+			content: "ouput:\n  dir: /tmp\n",
+			err:     true,
+		},
+		{
+			name:    "Malformed",
+			content: "input: [",
+			err:     true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			config, err := Load(path)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, but got %v", tc.err, err)
+			}
+			if tc.check != nil {
+				tc.check(t, config)
+			}
+		})
+	}
+}
+
+func TestAnalysis(t *testing.T) {
+	type testcase struct {
+		name   string
+		modify func(config *Config)
+		err    bool
+		check  func(t *testing.T, analysis app.Config)
+	}
+
+	testcases := []testcase{
+		{
+			name:   "Default",
+			modify: func(config *Config) {},
+			check: func(t *testing.T, analysis app.Config) {
+				if analysis.Location != time.UTC {
+					t.Errorf("Expected get %v, but got %v", time.UTC, analysis.Location)
+				}
+				if analysis.Peak != nil {
+					t.Errorf("Expected peak analysis disabled, but got %v", analysis.Peak)
+				}
+				if analysis.Outlier.Method != app.OutlierIQR || analysis.Outlier.Multiplier != 1.5 {
+					t.Errorf("Expected get iqr 1.5, but got %v", analysis.Outlier)
+				}
+			},
+		},
+		{
+			name: "Peak",
+			modify: func(config *Config) {
+				config.Peak.Enabled = true
+				config.Peak.Hours = "19:00-23:00"
+				config.Peak.Timezone = "Europe/London"
+			},
+			check: func(t *testing.T, analysis app.Config) {
+				if analysis.Peak == nil || analysis.Peak.Start != 19*60 || analysis.Peak.End != 23*60 || analysis.Peak.Location.String() != "Europe/London" {
+					t.Errorf("Expected get 19:00-23:00 Europe/London, but got %v", analysis.Peak)
+				}
+			},
+		},
+		{
+			name:   "Empty input dir",
+			modify: func(config *Config) { config.Input.Dir = "" },
+			err:    true,
+		},
+		{
+			name:   "Unknown format",
+			modify: func(config *Config) { config.Output.Formats = []string{"pdf"} },
+			err:    true,
+		},
+		{
+			name:   "Unknown timezone",
+			modify: func(config *Config) { config.Timezone = "Mars/Olympus" },
+			err:    true,
+		},
+		{
+			name:   "Unknown input timezone",
+			modify: func(config *Config) { config.InputTimezones = map[string]string{"1.json": "Mars/Olympus"} },
+			err:    true,
+		},
+		{
+			name:   "Unknown aggregation",
+			modify: func(config *Config) { config.Aggregations = []string{"year"} },
+			err:    true,
+		},
+		{
+			name: "Invalid peak hours",
+			modify: func(config *Config) {
+				config.Peak.Enabled = true
+				config.Peak.Hours = "evening"
+			},
+			err: true,
+		},
+		{
+			name:   "Unknown outlier method",
+			modify: func(config *Config) { config.Outlier.Method = "grubbs" },
+			err:    true,
+		},
+		{
+			name:   "Negative multiplier",
+			modify: func(config *Config) { config.Outlier.Multiplier = -1 },
+			err:    true,
+		},
+		{
+			name:   "Unknown unit",
+			modify: func(config *Config) { config.Unit = "MB/s" },
+			err:    true,
+		},
+		{
+			name:   "Missing template file",
+			modify: func(config *Config) { config.Output.Templates = map[string]string{"text": "missing.tmpl"} },
+			err:    true,
+		},
+		{
+			name:   "Template of JSON",
+			modify: func(config *Config) { config.Output.Templates = map[string]string{"json": "missing.tmpl"} },
+			err:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := Default()
+			tc.modify(&config)
+
+			analysis, err := config.Analysis()
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, but got %v", tc.err, err)
+			}
+			if tc.check != nil {
+				tc.check(t, analysis)
+			}
+		})
+	}
+}
+
+func TestAnalysisTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.tmpl")
+	err := os.WriteFile(path, []byte("{{.Name}}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := Default()
+	config.Output.Templates = map[string]string{"markdown": path}
+	analysis, err := config.Analysis()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := analysis.Templates[app.FormatMarkdown]; !ok || len(analysis.Templates) != 1 {
+		t.Errorf("Expected get markdown template, but got %v", analysis.Templates)
+	}
+
+	err = os.WriteFile(path, []byte("{{.Name"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = config.Analysis()
+	if err == nil {
+		t.Errorf("Expected get error for invalid template, but got nil")
+	}
+}
+
+func TestPostgresOptions(t *testing.T) {
+	type testcase struct {
+		name     string
+		timezone string
+		from     string
+		to       string
+		err      bool
+		expected [2]time.Time
+	}
+
+	london, _ := time.LoadLocation("Europe/London")
+
+	testcases := []testcase{
+		{
+			name:     "Unbounded",
+			timezone: "UTC",
+		},
+		{
+			name:     "Date in timezone",
+			timezone: "Europe/London",
+			from:     "2021-06-01",
+			to:       "2021-07-01T00:00:00Z",
+			expected: [2]time.Time{time.Date(2021, 6, 1, 0, 0, 0, 0, london), time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:     "Invalid",
+			timezone: "UTC",
+			from:     "yesterday",
+			err:      true,
+		},
+		{
+			name:     "Reversed",
+			timezone: "UTC",
+			from:     "2021-07-01",
+			to:       "2021-06-01",
+			err:      true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := Default()
+			config.Timezone = tc.timezone
+			config.Input.Postgres.From = tc.from
+			config.Input.Postgres.To = tc.to
+
+			options, err := config.PostgresOptions()
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, but got %v", tc.err, err)
+			}
+			if tc.err {
+				return
+			}
+			if !options.From.Equal(tc.expected[0]) || !options.To.Equal(tc.expected[1]) {
+				t.Errorf("Expected get %v to %v, but got %v to %v", tc.expected[0], tc.expected[1], options.From, options.To)
+			}
+			if options.Table != "measurements" {
+				t.Errorf("Expected get %v, but got %v", "measurements", options.Table)
+			}
+		})
+	}
+}
+
+func TestLineProtocolOptions(t *testing.T) {
+	type testcase struct {
+		name   string
+		modify func(config *Config)
+		err    bool
+	}
+
+	testcases := []testcase{
+		{
+			name:   "Default",
+			modify: func(config *Config) {},
+		},
+		{
+			name:   "Unknown precision",
+			modify: func(config *Config) { config.Input.LineProtocol.Precision = "h" },
+			err:    true,
+		},
+		{
+			name:   "Empty field",
+			modify: func(config *Config) { config.Input.LineProtocol.Field = "" },
+			err:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := Default()
+			tc.modify(&config)
+
+			_, err := config.LineProtocolOptions()
+			if (err != nil) != tc.err {
+				t.Errorf("Expected error %v, but got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestReaderOptions(t *testing.T) {
+	type testcase struct {
+		name       string
+		modify     func(config *Config)
+		extensions []string
+		err        bool
+	}
+
+	testcases := []testcase{
+		{
+			name:       "Default",
+			modify:     func(config *Config) {},
+			extensions: []string{".json"},
+		},
+		{
+			name:       "Extension without dot",
+			modify:     func(config *Config) { config.Input.Extensions = []string{"json", ".ndjson"} },
+			extensions: []string{".json", ".ndjson"},
+		},
+		{
+			name:   "Unknown symlink policy",
+			modify: func(config *Config) { config.Input.Symlinks = "copy" },
+			err:    true,
+		},
+		{
+			name:   "Invalid pattern",
+			modify: func(config *Config) { config.Input.Include = []string{"[a-"} },
+			err:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := Default()
+			tc.modify(&config)
+
+			options, err := config.ReaderOptions()
+			if (err != nil) != tc.err {
+				t.Errorf("Expected error %v, but got %v", tc.err, err)
+			}
+			if err == nil && !reflect.DeepEqual(options.Extensions, tc.extensions) {
+				t.Errorf("Expected get %v, but got %v", tc.extensions, options.Extensions)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// cache persisted as JSON file, so entries survive restart
+type fileCache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+// function to load cache from path, missing file is treated as empty cache
+func NewFileCache(path string) (*fileCache, error) {
+	c := &fileCache{
+		path:    path,
+		entries: make(map[string]string),
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &c.entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// function to hash content into cache key
+func Key(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// function to check whether entry is stored with this key
+func (c *fileCache) Hit(name string, key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries[name] == key
+}
+
+// function to store entry and persist the cache
+func (c *fileCache) Store(name string, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[name] = key
+	return c.save()
+}
+
+// function to write cache to temporary file then rename it, so cache is never half written
+func (c *fileCache) save() error {
+	content, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	c, err := NewFileCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Hit("1.json", "key") {
+		t.Errorf("Expected get miss on empty cache, but got hit")
+	}
+	err = c.Store("1.json", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewFileCache(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.Hit("1.json", "key") || loaded.Hit("1.json", "other") || loaded.Hit("2.json", "key") {
+		t.Errorf("Expected get only 1.json with key hit, but got %v", loaded.entries)
+	}
+
+	os.WriteFile(path, []byte("{"), 0644)
+	_, err = NewFileCache(path)
+	if err == nil {
+		t.Errorf("Expected get error, but got nil")
+	}
+}
@@ -0,0 +1,7 @@
+package cache
+
+// interface that expect to be provided in cache implementation, key is content hash of whatever the entry is derived from
+type Cache interface {
+	Hit(name string, key string) bool
+	Store(name string, key string) error
+}
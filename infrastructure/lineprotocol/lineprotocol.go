@@ -0,0 +1,329 @@
+package lineprotocol
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unit of timestamp in line protocol
+type Precision string
+
+const (
+	PrecisionNanosecond  Precision = "ns"
+	PrecisionMicrosecond Precision = "us"
+	PrecisionMillisecond Precision = "ms"
+	PrecisionSecond      Precision = "s"
+)
+
+// function to parse precision name provided by user
+func ParsePrecision(name string) (Precision, error) {
+	switch Precision(name) {
+	case PrecisionNanosecond, PrecisionMicrosecond, PrecisionMillisecond, PrecisionSecond:
+		return Precision(name), nil
+	}
+
+	return "", fmt.Errorf("unknown precision %q (expected ns, us, ms or s)", name)
+}
+
+func (p Precision) duration() time.Duration {
+	switch p {
+	case PrecisionMicrosecond:
+		return time.Microsecond
+	case PrecisionMillisecond:
+		return time.Millisecond
+	case PrecisionSecond:
+		return time.Second
+	}
+
+	return time.Nanosecond
+}
+
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// field value is float64, int64, uint64, string or bool
+type Field struct {
+	Key   string
+	Value any
+}
+
+// one point of line protocol: measurement,tag=value field=value timestamp
+type Point struct {
+	Measurement string
+	Tags        []Tag
+	Fields      []Field
+	// zero when timestamp is omitted
+	Time time.Time
+}
+
+// function to get value of tag
+func (p Point) Tag(key string) (string, bool) {
+	for _, tag := range p.Tags {
+		if tag.Key == key {
+			return tag.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// function to get value of numeric field as float64
+func (p Point) Float(key string) (float64, bool) {
+	for _, field := range p.Fields {
+		if field.Key != key {
+			continue
+		}
+
+		switch value := field.Value.(type) {
+		case float64:
+			return value, true
+		case int64:
+			return float64(value), true
+		case uint64:
+			return float64(value), true
+		}
+		return 0, false
+	}
+
+	return 0, false
+}
+
+// function to parse every point in content, blank line and comment (starting with #) are skipped
+func Parse(content []byte, precision Precision) ([]Point, error) {
+	result := make([]Point, 0)
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		point, err := ParseLine(text, precision)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		result = append(result, point)
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// function to parse one line of line protocol
+func ParseLine(line string, precision Precision) (Point, error) {
+	point := Point{}
+	p := parser{line: line}
+
+	point.Measurement = p.token(", ", false)
+	if point.Measurement == "" {
+		return Point{}, fmt.Errorf("missing measurement")
+	}
+
+	for p.peek() == ',' {
+		p.pos++
+		key := p.token("=", true)
+		if !p.consume('=') || key == "" {
+			return Point{}, fmt.Errorf("invalid tag at column %d", p.pos+1)
+		}
+		value := p.token(", ", true)
+		if value == "" {
+			return Point{}, fmt.Errorf("empty value of tag %q", key)
+		}
+		point.Tags = append(point.Tags, Tag{Key: key, Value: value})
+	}
+
+	if !p.consume(' ') {
+		return Point{}, fmt.Errorf("missing field set")
+	}
+	for {
+		key := p.token("=", true)
+		if !p.consume('=') || key == "" {
+			return Point{}, fmt.Errorf("invalid field at column %d", p.pos+1)
+		}
+		value, err := p.fieldValue()
+		if err != nil {
+			return Point{}, fmt.Errorf("field %q: %w", key, err)
+		}
+		point.Fields = append(point.Fields, Field{Key: key, Value: value})
+
+		if !p.consume(',') {
+			break
+		}
+	}
+
+	if p.consume(' ') {
+		timestamp := strings.TrimSpace(line[p.pos:])
+		value, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return Point{}, fmt.Errorf("invalid timestamp %q", timestamp)
+		}
+		point.Time = time.Unix(0, value*int64(precision.duration())).UTC()
+		p.pos = len(line)
+	}
+	if p.pos != len(line) {
+		return Point{}, fmt.Errorf("unexpected character at column %d", p.pos+1)
+	}
+
+	return point, nil
+}
+
+type parser struct {
+	line string
+	pos  int
+}
+
+func (p *parser) peek() byte {
+	if p.pos >= len(p.line) {
+		return 0
+	}
+	return p.line[p.pos]
+}
+
+func (p *parser) consume(c byte) bool {
+	if p.peek() != c {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+// function to read until unescaped stop character, backslash escape comma, space (and equal sign when escapeEqual)
+func (p *parser) token(stop string, escapeEqual bool) string {
+	var sb strings.Builder
+	for p.pos < len(p.line) {
+		c := p.line[p.pos]
+		if c == '\\' && p.pos+1 < len(p.line) {
+			next := p.line[p.pos+1]
+			if next == ',' || next == ' ' || (escapeEqual && next == '=') || next == '\\' {
+				sb.WriteByte(next)
+				p.pos += 2
+				continue
+			}
+		}
+		if strings.IndexByte(stop, c) >= 0 {
+			break
+		}
+		sb.WriteByte(c)
+		p.pos++
+	}
+
+	return sb.String()
+}
+
+func (p *parser) fieldValue() (any, error) {
+	if p.consume('"') {
+		var sb strings.Builder
+		for p.pos < len(p.line) {
+			c := p.line[p.pos]
+			if c == '\\' && p.pos+1 < len(p.line) && (p.line[p.pos+1] == '"' || p.line[p.pos+1] == '\\') {
+				sb.WriteByte(p.line[p.pos+1])
+				p.pos += 2
+				continue
+			}
+			p.pos++
+			if c == '"' {
+				return sb.String(), nil
+			}
+			sb.WriteByte(c)
+		}
+		return nil, fmt.Errorf("unterminated string")
+	}
+
+	start := p.pos
+	for p.pos < len(p.line) && p.line[p.pos] != ',' && p.line[p.pos] != ' ' {
+		p.pos++
+	}
+	raw := p.line[start:p.pos]
+
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	case "":
+		return nil, fmt.Errorf("missing value")
+	}
+
+	switch raw[len(raw)-1] {
+	case 'i':
+		return strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+	case 'u':
+		return strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("invalid number %q", raw)
+	}
+	return value, nil
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(`\`, `\\`, ",", `\,`, " ", `\ `)
+	keyEscaper         = strings.NewReplacer(`\`, `\\`, ",", `\,`, "=", `\=`, " ", `\ `)
+	stringEscaper      = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+)
+
+// function to encode point in nanosecond precision, tags are sorted by key as recommended for write performance
+func (p Point) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(measurementEscaper.Replace(p.Measurement))
+
+	tags := append([]Tag(nil), p.Tags...)
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].Key < tags[j].Key
+	})
+	for _, tag := range tags {
+		if tag.Value == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, ",%s=%s", keyEscaper.Replace(tag.Key), keyEscaper.Replace(tag.Value))
+	}
+
+	for i, field := range p.Fields {
+		if i == 0 {
+			sb.WriteByte(' ')
+		} else {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(keyEscaper.Replace(field.Key))
+		sb.WriteByte('=')
+
+		switch value := field.Value.(type) {
+		case float64:
+			sb.WriteString(strconv.FormatFloat(value, 'f', -1, 64))
+		case int:
+			fmt.Fprintf(&sb, "%di", value)
+		case int64:
+			fmt.Fprintf(&sb, "%di", value)
+		case uint64:
+			fmt.Fprintf(&sb, "%du", value)
+		case bool:
+			sb.WriteString(strconv.FormatBool(value))
+		default:
+			fmt.Fprintf(&sb, `"%s"`, stringEscaper.Replace(fmt.Sprint(value)))
+		}
+	}
+
+	if !p.Time.IsZero() {
+		fmt.Fprintf(&sb, " %d", p.Time.UnixNano())
+	}
+
+	return sb.String()
+}
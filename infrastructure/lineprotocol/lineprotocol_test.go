@@ -0,0 +1,135 @@
+package lineprotocol
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	type testcase struct {
+		name      string
+		line      string
+		precision Precision
+		expected  Point
+		err       bool
+	}
+
+	testcases := []testcase{
+		{
+			name: "Full",
+			line: `download,device=1,region=eu metric_value=1000.5,count=3i,total=4u,ok=t,note="a \"b\"" 1609459200000000000`,
+			expected: Point{
+				Measurement: "download",
+				Tags:        []Tag{{Key: "device", Value: "1"}, {Key: "region", Value: "eu"}},
+				Fields: []Field{
+					{Key: "metric_value", Value: 1000.5},
+					{Key: "count", Value: int64(3)},
+					{Key: "total", Value: uint64(4)},
+					{Key: "ok", Value: true},
+					{Key: "note", Value: `a "b"`},
+				},
+				Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:      "Escape and precision",
+			line:      `my\ download,device\=id=a\,b metric\ value=1 1609459200`,
+			precision: PrecisionSecond,
+			expected: Point{
+				Measurement: "my download",
+				Tags:        []Tag{{Key: "device=id", Value: "a,b"}},
+				Fields:      []Field{{Key: "metric value", Value: 1.0}},
+				Time:        time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "No timestamp",
+			line: `download metric_value=-1e3`,
+			expected: Point{
+				Measurement: "download",
+				Fields:      []Field{{Key: "metric_value", Value: -1000.0}},
+			},
+		},
+		{
+			name: "Missing field",
+			line: `download,device=1`,
+			err:  true,
+		},
+		{
+			name: "Invalid number",
+			line: `download metric_value=fast`,
+			err:  true,
+		},
+		{
+			name: "Unterminated string",
+			line: `download note="abc`,
+			err:  true,
+		},
+		{
+			name: "Invalid timestamp",
+			line: `download metric_value=1 yesterday`,
+			err:  true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			point, err := ParseLine(tc.line, tc.precision)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, but got %v", tc.err, err)
+			}
+			if !tc.err && !reflect.DeepEqual(point, tc.expected) {
+				t.Errorf("Expected get %+v, but got %+v", tc.expected, point)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	content := "# comment\n\ndownload metric_value=1 1\ndownload metric_value=2 2\n"
+
+	_, err := Parse([]byte(content+"download metric_value\n"), PrecisionNanosecond)
+	if err == nil || err.Error() != `line 5: invalid field at column 22` {
+		t.Errorf("Expected get error on line 5, but got %v", err)
+	}
+
+	points, err := Parse([]byte(content), PrecisionNanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Errorf("Expected get 2, but got %d", len(points))
+	}
+}
+
+func TestPointString(t *testing.T) {
+	point := Point{
+		Measurement: "performance summary",
+		Tags:        []Tag{{Key: "unit", Value: "Mbit/s"}, {Key: "device", Value: "a,b=c"}, {Key: "empty", Value: ""}},
+		Fields: []Field{
+			{Key: "mean", Value: 12.5},
+			{Key: "count", Value: 3},
+			{Key: "under_performing", Value: false},
+			{Key: "period", Value: `say "hi"`},
+		},
+		Time: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	expected := `performance\ summary,device=a\,b\=c,unit=Mbit/s mean=12.5,count=3i,under_performing=false,period="say \"hi\"" 1609459200000000000`
+	if point.String() != expected {
+		t.Errorf("Expected get %s, but got %s", expected, point.String())
+	}
+
+	// encoded point is parsed back to the same values
+	parsed, err := ParseLine(point.String(), PrecisionNanosecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := parsed.Tag("device"); value != "a,b=c" {
+		t.Errorf("Expected get %s, but got %s", "a,b=c", value)
+	}
+	if value, _ := parsed.Float("count"); value != 3 {
+		t.Errorf("Expected get %v, but got %v", 3, value)
+	}
+}
@@ -0,0 +1,156 @@
+package openmetrics
+
+import (
+	"container/list"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// media type of OpenMetrics text exposition
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// gauge exposed for every device
+type metric struct {
+	name  string
+	unit  string
+	help  string
+	value func(report types.Report) float64
+}
+
+var metrics = []metric{
+	{
+		name:  "performance_analyser_mean_bytes_per_second",
+		unit:  "bytes_per_second",
+		help:  "Mean download speed of the analysed period.",
+		value: func(report types.Report) float64 { return report.Statistics.Mean },
+	},
+	{
+		name:  "performance_analyser_median_bytes_per_second",
+		unit:  "bytes_per_second",
+		help:  "Median download speed of the analysed period.",
+		value: func(report types.Report) float64 { return report.Statistics.Median },
+	},
+	{
+		name:  "performance_analyser_first_quartile_bytes_per_second",
+		unit:  "bytes_per_second",
+		help:  "First quartile of download speed of the analysed period.",
+		value: func(report types.Report) float64 { return report.Statistics.FirstQuartile },
+	},
+	{
+		name:  "performance_analyser_third_quartile_bytes_per_second",
+		unit:  "bytes_per_second",
+		help:  "Third quartile of download speed of the analysed period.",
+		value: func(report types.Report) float64 { return report.Statistics.FirstQuartile + report.Statistics.IQR },
+	},
+	{
+		name:  "performance_analyser_min_bytes_per_second",
+		unit:  "bytes_per_second",
+		help:  "Minimum download speed of the analysed period.",
+		value: func(report types.Report) float64 { return report.Statistics.Min },
+	},
+	{
+		name:  "performance_analyser_max_bytes_per_second",
+		unit:  "bytes_per_second",
+		help:  "Maximum download speed of the analysed period.",
+		value: func(report types.Report) float64 { return report.Statistics.Max },
+	},
+	{
+		name:  "performance_analyser_measurements",
+		help:  "Number of measurements in the analysed period.",
+		value: func(report types.Report) float64 { return float64(report.Statistics.Count) },
+	},
+	{
+		name:  "performance_analyser_under_performing_days",
+		help:  "Number of days with under-performing measurement.",
+		value: func(report types.Report) float64 { return float64(report.UnderPerformingDays) },
+	},
+	{
+		name:  "performance_analyser_period_end_timestamp_seconds",
+		unit:  "seconds",
+		help:  "Time of the last measurement in the analysed period.",
+		value: func(report types.Report) float64 { return float64(report.To.Unix()) },
+	},
+}
+
+// latest report of every device, safe for concurrent use
+type Registry struct {
+	mu      sync.RWMutex
+	reports map[string]types.Report
+	// maximum number of devices kept, 0 means unlimited
+	maxDevices int
+	// devices from the least to the most recently observed, so the oldest is evicted first when there are too many
+	recent   *list.List
+	elements map[string]*list.Element
+}
+
+func NewRegistry() *Registry {
+	return NewRegistryWithLimit(0)
+}
+
+// function to make registry keeping at most maxDevices devices (0 means unlimited), device observed least recently is evicted when new device exceed the limit
+// device name (e.g. query parameter of HTTP API) become metric label, so the limit keep number of time series bounded
+func NewRegistryWithLimit(maxDevices int) *Registry {
+	return &Registry{
+		reports:    make(map[string]types.Report),
+		maxDevices: maxDevices,
+		recent:     list.New(),
+		elements:   make(map[string]*list.Element),
+	}
+}
+
+// function to replace the report of device
+func (r *Registry) Observe(device string, report types.Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reports[device] = report
+	if r.maxDevices <= 0 {
+		return
+	}
+
+	if element, ok := r.elements[device]; ok {
+		r.recent.MoveToBack(element)
+		return
+	}
+	r.elements[device] = r.recent.PushBack(device)
+	for len(r.reports) > r.maxDevices {
+		oldest := r.recent.Remove(r.recent.Front()).(string)
+		delete(r.elements, oldest)
+		delete(r.reports, oldest)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// function to write every metric in OpenMetrics text format (also accepted by Prometheus textfile collector)
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.RLock()
+	devices := make([]string, 0, len(r.reports))
+	for device := range r.reports {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+
+	var sb strings.Builder
+	for _, m := range metrics {
+		fmt.Fprintf(&sb, "# TYPE %s gauge\n", m.name)
+		if m.unit != "" {
+			fmt.Fprintf(&sb, "# UNIT %s %s\n", m.name, m.unit)
+		}
+		fmt.Fprintf(&sb, "# HELP %s %s\n", m.name, m.help)
+		for _, device := range devices {
+			fmt.Fprintf(&sb, "%s{device=\"%s\"} %s\n", m.name, labelEscaper.Replace(device), strconv.FormatFloat(m.value(r.reports[device]), 'g', -1, 64))
+		}
+	}
+	sb.WriteString("# EOF\n")
+	r.mu.RUnlock()
+
+	n, err := io.WriteString(w, sb.String())
+	return int64(n), err
+}
@@ -0,0 +1,106 @@
+package openmetrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestRegistryWriteTo(t *testing.T) {
+	registry := NewRegistry()
+	registry.Observe("2", types.Report{
+		To:         time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		Statistics: types.Statistics{Count: 2, Min: 1, Max: 3, Mean: 2, Median: 2, FirstQuartile: 1.5, IQR: 1},
+	})
+	registry.Observe(`a "quoted" device`, types.Report{})
+	registry.Observe("1", types.Report{
+		To:                  time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		Statistics:          types.Statistics{Count: 60, Mean: 11685349.45704839},
+		UnderPerformingDays: 5,
+	})
+	// latest report replace previous one
+	registry.Observe("1", types.Report{
+		To:                  time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		Statistics:          types.Statistics{Count: 60, Mean: 11685349.45704839},
+		UnderPerformingDays: 5,
+	})
+
+	var sb strings.Builder
+	_, err := registry.WriteTo(&sb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := sb.String()
+
+	expected := []string{
+		"# TYPE performance_analyser_mean_bytes_per_second gauge\n# UNIT performance_analyser_mean_bytes_per_second bytes_per_second\n# HELP performance_analyser_mean_bytes_per_second Mean download speed of the analysed period.\nperformance_analyser_mean_bytes_per_second{device=\"1\"} 1.168534945704839e+07\nperformance_analyser_mean_bytes_per_second{device=\"2\"} 2\nperformance_analyser_mean_bytes_per_second{device=\"a \\\"quoted\\\" device\"} 0\n",
+		"performance_analyser_third_quartile_bytes_per_second{device=\"2\"} 2.5\n",
+		"# TYPE performance_analyser_under_performing_days gauge\n# HELP performance_analyser_under_performing_days Number of days with under-performing measurement.\nperformance_analyser_under_performing_days{device=\"1\"} 5\n",
+		"performance_analyser_period_end_timestamp_seconds{device=\"1\"} 1.6145568e+09\n",
+	}
+	for _, e := range expected {
+		if !strings.Contains(content, e) {
+			t.Errorf("Expected get content containing %q, but got %s", e, content)
+		}
+	}
+	if !strings.HasSuffix(content, "\n# EOF\n") {
+		t.Errorf("Expected get content ending with # EOF, but got %s", content)
+	}
+}
+
+func TestRegistryWithLimit(t *testing.T) {
+	type testcase struct {
+		name     string
+		limit    int
+		observed []string
+		result   []string
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Unlimited",
+			limit:    0,
+			observed: []string{"1", "2", "3"},
+			result:   []string{"1", "2", "3"},
+		},
+		{
+			name:     "Oldest evicted",
+			limit:    2,
+			observed: []string{"1", "2", "3"},
+			result:   []string{"2", "3"},
+		},
+		{
+			name:     "Observed again is kept",
+			limit:    2,
+			observed: []string{"1", "2", "1", "3"},
+			result:   []string{"1", "3"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := NewRegistryWithLimit(tc.limit)
+			for _, device := range tc.observed {
+				registry.Observe(device, types.Report{})
+			}
+
+			var sb strings.Builder
+			_, err := registry.WriteTo(&sb)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			devices := make([]string, 0)
+			for _, line := range strings.Split(sb.String(), "\n") {
+				if strings.HasPrefix(line, "performance_analyser_measurements{") {
+					devices = append(devices, strings.TrimSuffix(strings.TrimPrefix(line, `performance_analyser_measurements{device="`), `"} 0`))
+				}
+			}
+			if strings.Join(devices, ",") != strings.Join(tc.result, ",") {
+				t.Errorf("Expected get %v, but got %v", tc.result, devices)
+			}
+		})
+	}
+}
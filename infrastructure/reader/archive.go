@@ -0,0 +1,253 @@
+package reader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// function to open decompressing reader over r
+type decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// decompressor keyed by file extension
+var decompressors = map[string]decompressor{
+	".gz": func(r io.Reader) (io.ReadCloser, error) {
+		return gzip.NewReader(r)
+	},
+	".zst": func(r io.Reader) (io.ReadCloser, error) {
+		decoder, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return decoder.IOReadCloser(), nil
+	},
+	".bz2": func(r io.Reader) (io.ReadCloser, error) {
+		return io.NopCloser(bzip2.NewReader(r)), nil
+	},
+}
+
+// function to remove compression extension from name, "device.json.gz" become "device.json"
+func uncompressedName(name string) string {
+	if _, ok := decompressors[strings.ToLower(path.Ext(name))]; ok {
+		return strings.TrimSuffix(name, path.Ext(name))
+	}
+
+	return name
+}
+
+// function to tell whether name is archive whose entries are read as separate inputs
+func isArchive(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasSuffix(name, ".tgz") || strings.HasSuffix(name, ".zip") {
+		return true
+	}
+
+	return path.Ext(uncompressedName(name)) == ".tar"
+}
+
+// function to read file, decompressing it according to its extension, content larger than maxSize bytes (0 means unlimited) is rejected
+func readFile(fsys fs.FS, file string, maxSize int64) ([]byte, error) {
+	f, err := fsys.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readContent(file, f, maxSize)
+}
+
+// function to read content of file from r, decompressing it according to file extension, content larger than maxSize bytes (0 means unlimited) after decompression is rejected
+func readContent(file string, r io.Reader, maxSize int64) ([]byte, error) {
+	rc, err := decompress(file, r)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return readLimited(file, rc, maxSize)
+}
+
+// function to read r until EOF, failing as soon as more than maxSize bytes (0 means unlimited) are read so small compressed file cannot expand without bound
+func readLimited(file string, r io.Reader, maxSize int64) ([]byte, error) {
+	if maxSize <= 0 {
+		return io.ReadAll(r)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(r, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if int64(len(content)) > maxSize {
+		return nil, fmt.Errorf("%s: larger than maximum input size of %d bytes", file, maxSize)
+	}
+
+	return content, nil
+}
+
+// function to wrap r with decompressor of file extension, r is returned as is for uncompressed file
+func decompress(file string, r io.Reader) (io.ReadCloser, error) {
+	ext := strings.ToLower(path.Ext(file))
+	if ext == ".tgz" {
+		ext = ".gz"
+	}
+
+	open, ok := decompressors[ext]
+	if !ok {
+		return io.NopCloser(r), nil
+	}
+
+	result, err := open(r)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	return result, nil
+}
+
+// how entries of archive are read
+type archiveOptions struct {
+	// function to tell whether entry is wanted, checked before entry is read so unwanted entry is skipped without reading it (nil means every entry)
+	want func(name string) bool
+	// maximum size of one entry after decompression in bytes, 0 means unlimited
+	maxSize int64
+}
+
+// function to call visit with name and content of every wanted regular file in tar or zip archive, stop early when visit return errStopArchive
+func readArchive(fsys fs.FS, file string, options archiveOptions, visit func(name string, content []byte) error) error {
+	f, err := fsys.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return readArchiveContent(file, f, options, visit)
+}
+
+// function to call visit with every wanted regular file of archive read from r (e.g. content already read by watcher)
+func readArchiveContent(file string, r io.Reader, options archiveOptions, visit func(name string, content []byte) error) error {
+	if strings.HasSuffix(strings.ToLower(file), ".zip") {
+		return readZip(file, r, options, visit)
+	}
+
+	rc, err := decompress(file, r)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name, err := entryName(file, header.Name)
+		if err != nil {
+			return err
+		}
+		// content of skipped entry is discarded by the next call of Next
+		if options.want != nil && !options.want(name) {
+			continue
+		}
+		content, err := readLimited(file+"/"+name, tr, options.maxSize)
+		if err != nil {
+			return err
+		}
+		err = visit(name, content)
+		if errors.Is(err, errStopArchive) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func readZip(file string, r io.Reader, options archiveOptions, visit func(name string, content []byte) error) error {
+	// zip directory is at the end of file, so file is read into memory unless it support random access
+	var readerAt io.ReaderAt
+	var size int64
+	if f, ok := r.(fs.File); ok {
+		if ra, ok := r.(io.ReaderAt); ok {
+			info, err := f.Stat()
+			if err != nil {
+				return err
+			}
+			readerAt, size = ra, info.Size()
+		}
+	}
+	if readerAt == nil {
+		content, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		readerAt, size = bytes.NewReader(content), int64(len(content))
+	}
+
+	zr, err := zip.NewReader(readerAt, size)
+	if err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+
+	for _, entry := range zr.File {
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+
+		name, err := entryName(file, entry.Name)
+		if err != nil {
+			return err
+		}
+		if options.want != nil && !options.want(name) {
+			continue
+		}
+		r, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		// size in zip header is not trusted, content is limited while it is read
+		content, err := readLimited(file+"/"+name, r, options.maxSize)
+		r.Close()
+		if err != nil {
+			return err
+		}
+		err = visit(name, content)
+		if errors.Is(err, errStopArchive) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// returned by visit function to stop reading archive without error
+var errStopArchive = errors.New("stop reading archive")
+
+// function to clean entry name, entry escaping the archive (e.g. "../device.json") is rejected since name become part of output path
+func entryName(file string, name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(name, "./"))
+	if path.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%s: unsafe entry name %q", file, name)
+	}
+
+	return cleaned, nil
+}
@@ -0,0 +1,382 @@
+package reader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// bzip2 of [{"metricValue": 3, "dtime": "2021-01-01"}] (standard library can only decompress bzip2)
+var bzip2Input = []byte{
+	0x42, 0x5a, 0x68, 0x39, 0x31, 0x41, 0x59, 0x26, 0x53, 0x59, 0x1a, 0x43, 0x35, 0xa1, 0x00, 0x00,
+	0x0c, 0x1b, 0x80, 0x50, 0x06, 0x78, 0x10, 0x01, 0x0a, 0x2e, 0x26, 0x16, 0x0a, 0x20, 0x00, 0x31,
+	0x41, 0xa3, 0x46, 0x83, 0x20, 0x34, 0x1a, 0xa7, 0xe9, 0x26, 0x4f, 0x53, 0xd4, 0x32, 0x03, 0xda,
+	0xa3, 0xd0, 0xb6, 0x01, 0x97, 0x82, 0x1c, 0x44, 0x03, 0x0d, 0xa3, 0x0c, 0x5d, 0x5f, 0xba, 0xfa,
+	0x8c, 0xaf, 0xad, 0x6a, 0x4f, 0x89, 0x57, 0xf1, 0x77, 0x24, 0x53, 0x85, 0x09, 0x01, 0xa4, 0x33,
+	0x5a, 0x10,
+}
+
+func mesurementJSON(value string) []byte {
+	return []byte(`[{"metricValue": ` + value + `, "dtime": "2021-01-01"}]`)
+}
+
+func gzipped(t *testing.T, content []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(content)
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func zstded(t *testing.T, content []byte) []byte {
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write(content)
+	err = w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+type archiveEntry struct {
+	name    string
+	content []byte
+}
+
+func tarred(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	// directory entry is skipped
+	w.WriteHeader(&tar.Header{Name: "site/", Typeflag: tar.TypeDir, Mode: 0755})
+	for _, entry := range entries {
+		err := w.WriteHeader(&tar.Header{Name: entry.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(entry.content))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(entry.content)
+	}
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func zipped(t *testing.T, entries []archiveEntry) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		f, err := w.Create(entry.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write(entry.content)
+	}
+	err := w.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestIOReaderCompressed(t *testing.T) {
+	dir := t.TempDir()
+	entries := []archiveEntry{
+		{name: "./site/a.json", content: mesurementJSON("10")},
+		{name: "site/.a.json.swp", content: []byte("noise")},
+		{name: "readme.txt", content: []byte("noise")},
+		{name: "b.json", content: mesurementJSON("11")},
+	}
+	files := map[string][]byte{
+		"1.json.gz":        gzipped(t, mesurementJSON("1")),
+		"2.json.zst":       zstded(t, mesurementJSON("2")),
+		"3.json.bz2":       bzip2Input,
+		"4.txt.gz":         gzipped(t, []byte("noise")),
+		"export.tar":       tarred(t, entries),
+		"export.tar.gz":    gzipped(t, tarred(t, entries)),
+		"export.tar.zst":   zstded(t, tarred(t, entries)),
+		"export.zip":       zipped(t, entries),
+		"site/export.tgz":  gzipped(t, tarred(t, entries[3:])),
+		"site/plain.json":  mesurementJSON("12"),
+		"site/.hidden.zip": zipped(t, entries),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		err := os.WriteFile(path, content, 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := NewIOReaderWithOptions(dir, IOOptions{Recursive: true, Exclude: DefaultExclude, Extensions: DefaultExtensions})
+	inputs, err := r.GetInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]float64{
+		"1.json.gz":                  1,
+		"2.json.zst":                 2,
+		"3.json.bz2":                 3,
+		"export.tar/site/a.json":     10,
+		"export.tar/b.json":          11,
+		"export.tar.gz/site/a.json":  10,
+		"export.tar.gz/b.json":       11,
+		"export.tar.zst/site/a.json": 10,
+		"export.tar.zst/b.json":      11,
+		"export.zip/site/a.json":     10,
+		"export.zip/b.json":          11,
+		"site/export.tgz/b.json":     11,
+		"site/plain.json":            12,
+	}
+	result := make(map[string]float64)
+	for _, input := range inputs {
+		result[input.Name] = input.Content[0].MetricValue
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected get %v, but got %v", expected, result)
+	}
+
+	// single input is read by the same name
+	for name, value := range expected {
+		input, err := r.GetInput(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if input.Name != name || input.Content[0].MetricValue != value {
+			t.Errorf("Expected get %s with %v, but got %s with %v", name, value, input.Name, input.Content)
+		}
+	}
+
+	_, err = r.GetInput("export.zip/missing.json")
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected get %v, but got %v", os.ErrNotExist, err)
+	}
+}
+
+func TestIOReaderUnsafeArchiveEntry(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "evil.tar"), tarred(t, []archiveEntry{{name: "../escape.json", content: mesurementJSON("1")}}), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewIOReader(dir).GetInputs()
+	if err == nil {
+		t.Errorf("Expected get error, but got nil")
+	}
+}
+
+func TestIOReaderMaxFileSize(t *testing.T) {
+	type testcase struct {
+		name   string
+		file   string
+		input  []byte
+		inputs int
+		err    bool
+	}
+
+	small := mesurementJSON("1")
+	// still valid JSON, only larger than the limit
+	large := append([]byte("["+strings.Repeat(" ", 200)), small[1:]...)
+	testcases := []testcase{
+		{
+			name:   "Within limit",
+			file:   "small.json",
+			input:  small,
+			inputs: 1,
+		},
+		{
+			name:  "File over limit",
+			file:  "large.json",
+			input: large,
+			err:   true,
+		},
+		{
+			name:  "Decompressed over limit",
+			file:  "large.json.gz",
+			input: gzipped(t, large),
+			err:   true,
+		},
+		{
+			name:  "Tar entry over limit",
+			file:  "export.tar.gz",
+			input: gzipped(t, tarred(t, []archiveEntry{{name: "large.json", content: large}})),
+			err:   true,
+		},
+		{
+			name:  "Zip entry over limit",
+			file:  "export.zip",
+			input: zipped(t, []archiveEntry{{name: "large.json", content: large}}),
+			err:   true,
+		},
+		{
+			// entry that is not selected is skipped before it is read
+			name:   "Skipped entry over limit",
+			file:   "export.tar",
+			input:  tarred(t, []archiveEntry{{name: "large.txt", content: large}, {name: "small.json", content: small}}),
+			inputs: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			err := os.WriteFile(filepath.Join(dir, tc.file), tc.input, 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			options := DefaultIOOptions()
+			options.MaxFileSize = int64(len(small)) + 10
+			inputs, err := NewIOReaderWithOptions(dir, options).GetInputs()
+			if tc.err {
+				if err == nil || !strings.Contains(err.Error(), "maximum input size") {
+					t.Errorf("Expected get maximum input size error, but got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(inputs) != tc.inputs {
+				t.Errorf("Expected get %d input, but got %v", tc.inputs, inputs)
+			}
+		})
+	}
+}
+
+func TestReadArchiveSelection(t *testing.T) {
+	archive := tarred(t, []archiveEntry{
+		{name: "a.json", content: mesurementJSON("1")},
+		{name: "b.txt", content: []byte("noise")},
+		{name: "c.json", content: mesurementJSON("3")},
+	})
+
+	checked := make([]string, 0)
+	visited := make([]string, 0)
+	options := archiveOptions{
+		want: func(name string) bool {
+			checked = append(checked, name)
+			return strings.HasSuffix(name, ".json")
+		},
+	}
+	err := readArchiveContent("export.tar", bytes.NewReader(archive), options, func(name string, content []byte) error {
+		visited = append(visited, name)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every entry is checked, only wanted entries are read
+	if !reflect.DeepEqual(checked, []string{"a.json", "b.txt", "c.json"}) {
+		t.Errorf("Expected get %v, but got %v", []string{"a.json", "b.txt", "c.json"}, checked)
+	}
+	if !reflect.DeepEqual(visited, []string{"a.json", "c.json"}) {
+		t.Errorf("Expected get %v, but got %v", []string{"a.json", "c.json"}, visited)
+	}
+}
+
+func TestIOReaderSelected(t *testing.T) {
+	type testcase struct {
+		name     string
+		options  IOOptions
+		file     string
+		expected bool
+	}
+
+	recursive := DefaultIOOptions()
+	recursive.Recursive = true
+
+	testcases := []testcase{
+		{name: "JSON", options: DefaultIOOptions(), file: "1.json", expected: true},
+		{name: "Other extension", options: DefaultIOOptions(), file: "notes.txt", expected: false},
+		{name: "Hidden", options: DefaultIOOptions(), file: ".1.json", expected: false},
+		{name: "Compressed", options: DefaultIOOptions(), file: "1.json.gz", expected: true},
+		{name: "Archive", options: DefaultIOOptions(), file: "pack.tar.gz", expected: true},
+		{name: "Subdirectory", options: DefaultIOOptions(), file: "site/1.json", expected: false},
+		{name: "Subdirectory recursive", options: recursive, file: "site/1.json", expected: true},
+		{name: "Hidden directory", options: recursive, file: ".git/1.json", expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			selected := NewFSReader(nil, tc.options).Selected(tc.file)
+			if selected != tc.expected {
+				t.Errorf("Expected get %v, but got %v", tc.expected, selected)
+			}
+		})
+	}
+}
+
+func TestIOReaderDecodeFile(t *testing.T) {
+	type testcase struct {
+		name     string
+		file     string
+		content  []byte
+		expected []string
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Plain",
+			file:     "1.json",
+			content:  mesurementJSON("1"),
+			expected: []string{"1.json"},
+		},
+		{
+			name:     "Compressed",
+			file:     "1.json.gz",
+			content:  gzipped(t, mesurementJSON("1")),
+			expected: []string{"1.json.gz"},
+		},
+		{
+			name: "Archive",
+			file: "pack.tar.gz",
+			content: gzipped(t, tarred(t, []archiveEntry{
+				{name: "site/1.json", content: mesurementJSON("1")},
+				{name: "notes.txt", content: []byte("hello")},
+			})),
+			expected: []string{"pack.tar.gz/site/1.json"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			inputs, err := NewFSReader(nil, DefaultIOOptions()).DecodeFile(tc.file, tc.content)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := make([]string, 0)
+			for _, input := range inputs {
+				names = append(names, input.Name)
+			}
+			if !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("Expected get %v, but got %v", tc.expected, names)
+			}
+		})
+	}
+}
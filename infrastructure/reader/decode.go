@@ -0,0 +1,87 @@
+package reader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// function to decode mesurement series from JSON array
+func DecodeJSON(content []byte) ([]types.Mesurement, error) {
+	var mesurement []types.Mesurement
+	err := json.Unmarshal(content, &mesurement)
+	if err != nil {
+		return nil, err
+	}
+
+	return mesurement, nil
+}
+
+// function to decode mesurement series from CSV with header row containing dtime and metricValue column
+func DecodeCSV(content []byte) ([]types.Mesurement, error) {
+	csvReader := csv.NewReader(bytes.NewReader(content))
+	csvReader.TrimLeadingSpace = true
+
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, errors.New("missing CSV header")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dtimeColumn, metricValueColumn := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "dtime":
+			dtimeColumn = i
+		case "metricvalue":
+			metricValueColumn = i
+		}
+	}
+	if dtimeColumn == -1 || metricValueColumn == -1 {
+		return nil, errors.New("CSV header must contain dtime and metricValue column")
+	}
+
+	result := make([]types.Mesurement, 0)
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := csvReader.FieldPos(0)
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[metricValueColumn]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid metricValue %q", line, record[metricValueColumn])
+		}
+		// ParseFloat accept NaN and Inf, which JSON cannot express and would make every statistic meaningless
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, fmt.Errorf("line %d: metricValue %q is not a finite number", line, record[metricValueColumn])
+		}
+
+		var dtime types.JSONTime
+		err = dtime.UnmarshalJSON([]byte(strconv.Quote(strings.TrimSpace(record[dtimeColumn]))))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid dtime %q", line, record[dtimeColumn])
+		}
+
+		result = append(result, types.Mesurement{
+			MetricValue: value,
+			Dtime:       dtime,
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,122 @@
+package reader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+// function to add every real input file as seed
+func addInputSeeds(f *testing.F) {
+	files, err := filepath.Glob("../../app/testdata/corpus/input/*.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(content)
+	}
+}
+
+func FuzzDecodeJSON(f *testing.F) {
+	addInputSeeds(f)
+	for _, seed := range []string{`[]`, `null`, `{}`, `[{}]`, `[{"metricValue": 1e308, "dtime": "2021-01-01T00:00:00-23:59"}]`, `[{"metricValue": "1", "dtime": "2021-01-01"}]`, `[{"dtime": 20210101}]`} {
+		f.Add([]byte(seed))
+	}
+
+	f.Fuzz(func(t *testing.T, content []byte) {
+		mesurements, err := DecodeJSON(content)
+		if err != nil {
+			return
+		}
+
+		// decoded series survive encoding as RFC3339 timestamp and decoding again
+		type row struct {
+			MetricValue float64 `json:"metricValue"`
+			Dtime       string  `json:"dtime"`
+		}
+		rows := make([]row, 0, len(mesurements))
+		for _, mesurement := range mesurements {
+			rows = append(rows, row{MetricValue: mesurement.MetricValue, Dtime: mesurement.Dtime.Format(time.RFC3339Nano)})
+		}
+		encoded, err := json.Marshal(rows)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		again, err := DecodeJSON(encoded)
+		if err != nil {
+			t.Fatalf("Expected nil of %s, but got %v", encoded, err)
+		}
+		if len(again) != len(mesurements) {
+			t.Fatalf("Expected get %d mesurement, but got %d", len(mesurements), len(again))
+		}
+		for i := range again {
+			if again[i].MetricValue != mesurements[i].MetricValue || !again[i].Dtime.Equal(mesurements[i].Dtime.Time) {
+				t.Errorf("Expected get %v, but got %v", mesurements[i], again[i])
+			}
+		}
+	})
+}
+
+// function to make JSON input of n daily mesurements
+func syntheticJSON(n int) []byte {
+	var sb strings.Builder
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	step := 365 * 24 * time.Hour / time.Duration(n)
+	sb.WriteString("[")
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, `{"metricValue": %d.%03d, "dtime": "%s"}`, 12000000+i%1000000, i%1000, start.Add(time.Duration(i)*step).Format(time.RFC3339))
+	}
+	sb.WriteString("]")
+
+	return []byte(sb.String())
+}
+
+func BenchmarkDecodeJSON(b *testing.B) {
+	for _, size := range []int{1000, 100000, 10000000} {
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			if size > 1000000 && testing.Short() {
+				b.Skip("skipping large input in short mode")
+			}
+			content := syntheticJSON(size)
+			b.SetBytes(int64(len(content)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := DecodeJSON(content)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// reading through file system including directory listing and selection
+func BenchmarkIOReaderGetInputs(b *testing.B) {
+	fsys := fstest.MapFS{}
+	for i := 0; i < 100; i++ {
+		fsys[fmt.Sprintf("site-%d/device-%d.json", i%10, i)] = &fstest.MapFile{Data: syntheticJSON(1000)}
+	}
+	r := NewFSReader(fsys, IOOptions{Recursive: true, Extensions: DefaultExtensions})
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := r.GetInputs()
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -9,3 +9,15 @@ type Reader interface {
 	GetInputs() ([]types.InputFormat, error)
 	GetInput(name string) (types.InputFormat, error)
 }
+
+// interface that reader can additionally provide to expose undecoded input (e.g. for validation)
+type RawReader interface {
+	GetRawInputs() ([]types.RawInput, error)
+	GetRawInput(name string) (types.RawInput, error)
+}
+
+// interface that reader of input files can additionally provide, so file found by other means (e.g. watcher) is selected and decoded the same way as GetInputs
+type FileReader interface {
+	Selected(name string) bool
+	DecodeFile(name string, content []byte) ([]types.InputFormat, error)
+}
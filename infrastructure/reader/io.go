@@ -1,41 +1,110 @@
 package reader
 
 import (
-	"encoding/json"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"path"
+	"strings"
 
 	"github.com/awcjack/samknows-backend-code-test/types"
 )
 
 var (
-	basePath = "./input"
+	DefaultBasePath = "./input"
+	// hidden files (e.g. .gitkeep) and editor backup files are never analysed
+	DefaultExclude    = []string{".*", "*~", "*.swp"}
+	DefaultExtensions = []string{".json"}
+	// 1 GiB, far above real series but small enough that compressed or archived file cannot exhaust memory
+	DefaultMaxFileSize int64 = 1 << 30
 )
 
-type ioReader struct{}
+// how symbolic link found under input directory is handled
+type SymlinkPolicy string
 
-func NewIOReader() ioReader {
-	return ioReader{}
+const (
+	SymlinkFollow SymlinkPolicy = "follow"
+	SymlinkSkip   SymlinkPolicy = "skip"
+	// fail the read
+	SymlinkError SymlinkPolicy = "error"
+)
+
+// function to parse symlink policy name provided by user
+func ParseSymlinkPolicy(name string) (SymlinkPolicy, error) {
+	switch SymlinkPolicy(name) {
+	case SymlinkFollow, SymlinkSkip, SymlinkError:
+		return SymlinkPolicy(name), nil
+	}
+
+	return "", fmt.Errorf("unknown symlink policy %q (expected follow, skip or error)", name)
+}
+
+// options of file reader, zero value read every file directly under the directory and follow symlinks
+type IOOptions struct {
+	// read files in subdirectories as well, input name is the path relative to the directory (e.g. "site/device.json")
+	Recursive bool
+	// glob patterns that file must match, empty means every file
+	// pattern without "/" is matched against the file name, otherwise against the relative path where "**" match any number of directories
+	Include []string
+	// glob patterns of files and directories that are skipped
+	Exclude []string
+	// extensions (e.g. ".json") that file must have, empty means every extension
+	Extensions []string
+	Symlinks   SymlinkPolicy
+	// maximum size in bytes of one input file or archive entry after decompression, 0 means unlimited
+	MaxFileSize int64
+}
+
+func DefaultIOOptions() IOOptions {
+	return IOOptions{
+		Exclude:     DefaultExclude,
+		Extensions:  DefaultExtensions,
+		Symlinks:    SymlinkFollow,
+		MaxFileSize: DefaultMaxFileSize,
+	}
+}
+
+type ioReader struct {
+	fsys    fs.FS
+	options IOOptions
+}
+
+// function to make reader that read json files directly under basePath, skipping hidden and backup files
+func NewIOReader(basePath string) ioReader {
+	return NewIOReaderWithOptions(basePath, DefaultIOOptions())
+}
+
+// function to make reader that read input files under basePath selected by options
+func NewIOReaderWithOptions(basePath string, options IOOptions) ioReader {
+	return NewFSReader(os.DirFS(basePath), options)
+}
+
+// function to make reader that read input files from file system selected by options (e.g. fstest.MapFS in test or embed.FS)
+func NewFSReader(fsys fs.FS, options IOOptions) ioReader {
+	return ioReader{
+		fsys:    fsys,
+		options: options,
+	}
 }
 
 // Get all inputs files under directory
 func (r ioReader) GetInputs() ([]types.InputFormat, error) {
-	entries, err := os.ReadDir(basePath)
+	raws, err := r.GetRawInputs()
 	if err != nil {
 		return nil, err
 	}
 
 	result := make([]types.InputFormat, 0)
 
-	for _, entry := range entries {
-		// ignore directory
-		if !entry.IsDir() {
-			input, err := r.GetInput(entry.Name())
-			if err != nil {
-				return nil, err
-			}
-
-			result = append(result, input)
+	for _, raw := range raws {
+		input, err := decode(raw)
+		if err != nil {
+			return nil, err
 		}
+
+		result = append(result, input)
 	}
 
 	return result, nil
@@ -43,19 +112,336 @@ func (r ioReader) GetInputs() ([]types.InputFormat, error) {
 
 // Get input file based on name
 func (r ioReader) GetInput(name string) (types.InputFormat, error) {
-	content, err := os.ReadFile(basePath + "/" + name)
+	raw, err := r.GetRawInput(name)
 	if err != nil {
 		return types.InputFormat{}, err
 	}
 
-	var mesurement []types.Mesurement
-	err = json.Unmarshal(content, &mesurement)
+	return decode(raw)
+}
+
+func decode(raw types.RawInput) (types.InputFormat, error) {
+	mesurement, err := DecodeJSON(raw.Content)
 	if err != nil {
 		return types.InputFormat{}, err
 	}
 
 	return types.InputFormat{
-		Name:    name,
+		Name:    raw.Name,
 		Content: mesurement,
 	}, nil
 }
+
+// Get undecoded content of all inputs files under directory, every selected entry of archive is separate input named by archive path and entry path (e.g. "exports.tar.gz/device.json")
+func (r ioReader) GetRawInputs() ([]types.RawInput, error) {
+	names, err := r.names()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.RawInput, 0)
+
+	for _, name := range names {
+		if !isArchive(name) {
+			raw, err := r.GetRawInput(name)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, raw)
+			continue
+		}
+
+		f, err := r.fsys.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		entries, err := r.archiveInputs(name, f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, entries...)
+	}
+
+	return result, nil
+}
+
+// function to read every selected entry of archive as separate input
+func (r ioReader) archiveInputs(name string, content io.Reader) ([]types.RawInput, error) {
+	result := make([]types.RawInput, 0)
+
+	options := archiveOptions{
+		want: func(entry string) bool {
+			entry = name + "/" + entry
+			return !r.excluded(entry) && r.selected(entry)
+		},
+		maxSize: r.options.MaxFileSize,
+	}
+	err := readArchiveContent(name, content, options, func(entry string, content []byte) error {
+		result = append(result, types.RawInput{
+			Name:    name + "/" + entry,
+			Content: content,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Check whether file (path relative to the directory, e.g. found by watcher) is read by GetInputs
+func (r ioReader) Selected(name string) bool {
+	elements := strings.Split(path.Clean(name), "/")
+	if len(elements) > 1 && !r.options.Recursive {
+		return false
+	}
+	// file in excluded directory is skipped as well
+	for i := 1; i <= len(elements); i++ {
+		if r.excluded(strings.Join(elements[:i], "/")) {
+			return false
+		}
+	}
+
+	return isArchive(name) || r.selected(name)
+}
+
+// Decode content of file already read (e.g. by watcher) the same way as GetInputs, compressed content is decompressed and every selected entry of archive is separate input
+func (r ioReader) DecodeFile(name string, content []byte) ([]types.InputFormat, error) {
+	raws := make([]types.RawInput, 0)
+	if isArchive(name) {
+		entries, err := r.archiveInputs(name, bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+		raws = entries
+	} else {
+		decompressed, err := readContent(name, bytes.NewReader(content), r.options.MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		raws = append(raws, types.RawInput{Name: name, Content: decompressed})
+	}
+
+	result := make([]types.InputFormat, 0, len(raws))
+	for _, raw := range raws {
+		input, err := decode(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, input)
+	}
+
+	return result, nil
+}
+
+// Get undecoded content of input file based on name, compressed file is decompressed and name inside archive (e.g. "exports.tar.gz/device.json") is read from the archive
+func (r ioReader) GetRawInput(name string) (types.RawInput, error) {
+	if isArchive(name) {
+		return types.RawInput{}, fmt.Errorf("%s is an archive, its entries are read as separate inputs", name)
+	}
+
+	archive, entry := r.splitArchive(name)
+	if archive == "" {
+		content, err := readFile(r.fsys, name, r.options.MaxFileSize)
+		if err != nil {
+			return types.RawInput{}, err
+		}
+
+		return types.RawInput{
+			Name:    name,
+			Content: content,
+		}, nil
+	}
+
+	var result *types.RawInput
+	options := archiveOptions{
+		want: func(current string) bool {
+			return current == entry
+		},
+		maxSize: r.options.MaxFileSize,
+	}
+	err := readArchive(r.fsys, archive, options, func(current string, content []byte) error {
+		result = &types.RawInput{
+			Name:    name,
+			Content: content,
+		}
+		return errStopArchive
+	})
+	if err != nil {
+		return types.RawInput{}, err
+	}
+	if result == nil {
+		return types.RawInput{}, fmt.Errorf("%s: %w", name, fs.ErrNotExist)
+	}
+
+	return *result, nil
+}
+
+// function to split name into archive file and entry inside it, archive is empty when name is a plain file
+func (r ioReader) splitArchive(name string) (string, string) {
+	elements := strings.Split(name, "/")
+	for i := 1; i < len(elements); i++ {
+		archive := strings.Join(elements[:i], "/")
+		if !isArchive(archive) {
+			continue
+		}
+		info, err := fs.Stat(r.fsys, archive)
+		if err == nil && info.Mode().IsRegular() {
+			return archive, strings.Join(elements[i:], "/")
+		}
+	}
+
+	return "", ""
+}
+
+// function to list relative path of every selected input file in lexical order
+func (r ioReader) names() ([]string, error) {
+	result := make([]string, 0)
+
+	// ancestors are kept so symlink loop is not followed forever (only detectable on file system whose FileInfo support os.SameFile)
+	var walk func(dir string, ancestors []fs.FileInfo) error
+	walk = func(dir string, ancestors []fs.FileInfo) error {
+		info, err := fs.Stat(r.fsys, dir)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range ancestors {
+			if os.SameFile(ancestor, info) {
+				return nil
+			}
+		}
+		ancestors = append(ancestors, info)
+
+		entries, err := fs.ReadDir(r.fsys, dir)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			name := entry.Name()
+			if dir != "." {
+				name = dir + "/" + name
+			}
+			if r.excluded(name) {
+				continue
+			}
+
+			isDir := entry.IsDir()
+			if entry.Type()&fs.ModeSymlink != 0 {
+				switch r.options.Symlinks {
+				case SymlinkSkip:
+					continue
+				case SymlinkError:
+					return fmt.Errorf("input %s is a symbolic link", name)
+				}
+				info, err := fs.Stat(r.fsys, name)
+				if err != nil {
+					return err
+				}
+				isDir = info.IsDir()
+			}
+
+			if isDir {
+				if r.options.Recursive {
+					err := walk(name, ancestors)
+					if err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			// archive is always opened, its entries are selected instead
+			if isArchive(name) || r.selected(name) {
+				result = append(result, name)
+			}
+		}
+
+		return nil
+	}
+
+	err := walk(".", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// function to check whether file or directory matches any exclude pattern
+func (r ioReader) excluded(name string) bool {
+	for _, pattern := range r.options.Exclude {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// function to check whether file has wanted extension and matches include pattern, compressed file is checked without compression extension
+func (r ioReader) selected(name string) bool {
+	name = uncompressedName(name)
+	if len(r.options.Extensions) > 0 {
+		found := false
+		for _, extension := range r.options.Extensions {
+			if strings.EqualFold(path.Ext(name), extension) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(r.options.Include) == 0 {
+		return true
+	}
+	for _, pattern := range r.options.Include {
+		if matchGlob(pattern, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// function to match slash separated relative path against glob pattern, pattern without "/" is matched against the last element only and "**" element match any number of elements
+func matchGlob(pattern string, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+
+	return matchElements(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElements(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		ok, _ := path.Match(pattern[0], name[0])
+		if !ok {
+			return false
+		}
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) == 0
+}
@@ -0,0 +1,153 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// function to make input tree with nested directories, noise files and symlinks
+func makeTree(t *testing.T) string {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "root")
+	files := map[string]string{
+		"root/1.json":            `[{"metricValue": 1, "dtime": "2021-01-01"}]`,
+		"root/2.JSON":            `[{"metricValue": 2, "dtime": "2021-01-01"}]`,
+		"root/.gitkeep":          "",
+		"root/1.json~":           "",
+		"root/notes.txt":         "not input",
+		"root/site/3.json":       `[{"metricValue": 3, "dtime": "2021-01-01"}]`,
+		"root/site/.1.json.swp":  "",
+		"root/site/deep/4.json":  `[{"metricValue": 4, "dtime": "2021-01-01"}]`,
+		"root/archive/old.json":  `[{"metricValue": 5, "dtime": "2021-01-01"}]`,
+		"root/.hidden/6.json":    `[{"metricValue": 6, "dtime": "2021-01-01"}]`,
+		"outside/linked.json":    `[{"metricValue": 7, "dtime": "2021-01-01"}]`,
+		"outside/deep/more.json": `[{"metricValue": 8, "dtime": "2021-01-01"}]`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(path, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for link, target := range map[string]string{
+		"link.json": filepath.Join(dir, "outside/linked.json"),
+		"linkdir":   filepath.Join(dir, "outside/deep"),
+		// loop back to root
+		"site/loop": root,
+	} {
+		err := os.Symlink(target, filepath.Join(root, link))
+		if err != nil {
+			t.Skip("symlink not supported:", err)
+		}
+	}
+
+	return root
+}
+
+func TestIOReaderNames(t *testing.T) {
+	root := makeTree(t)
+
+	type testcase struct {
+		name     string
+		options  IOOptions
+		expected []string
+		err      bool
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Default",
+			options:  DefaultIOOptions(),
+			expected: []string{"1.json", "2.JSON", "link.json"},
+		},
+		{
+			name:     "Every file",
+			options:  IOOptions{},
+			expected: []string{".gitkeep", "1.json", "1.json~", "2.JSON", "link.json", "notes.txt"},
+		},
+		{
+			name:     "Recursive",
+			options:  IOOptions{Recursive: true, Exclude: DefaultExclude, Extensions: DefaultExtensions, Symlinks: SymlinkFollow},
+			expected: []string{"1.json", "2.JSON", "archive/old.json", "link.json", "linkdir/more.json", "site/3.json", "site/deep/4.json"},
+		},
+		{
+			name:     "Recursive skip symlinks",
+			options:  IOOptions{Recursive: true, Exclude: DefaultExclude, Extensions: DefaultExtensions, Symlinks: SymlinkSkip},
+			expected: []string{"1.json", "2.JSON", "archive/old.json", "site/3.json", "site/deep/4.json"},
+		},
+		{
+			name:    "Symlink error",
+			options: IOOptions{Exclude: DefaultExclude, Extensions: DefaultExtensions, Symlinks: SymlinkError},
+			err:     true,
+		},
+		{
+			name:     "Include and exclude",
+			options:  IOOptions{Recursive: true, Include: []string{"site/**/*.json", "1.*"}, Exclude: []string{".*", "*~", "site/deep"}, Extensions: DefaultExtensions, Symlinks: SymlinkSkip},
+			expected: []string{"1.json", "site/3.json"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := NewIOReaderWithOptions(root, tc.options).names()
+			if tc.err {
+				if err == nil {
+					t.Errorf("Expected get error, but got %v", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Expected get %v, but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestIOReaderGetInputs(t *testing.T) {
+	root := makeTree(t)
+
+	inputs, err := NewIOReaderWithOptions(root, IOOptions{Recursive: true, Include: []string{"site/**"}, Exclude: DefaultExclude, Symlinks: SymlinkSkip}).GetInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) != 2 || inputs[0].Name != "site/3.json" || inputs[1].Name != "site/deep/4.json" || inputs[1].Content[0].MetricValue != 4 {
+		t.Errorf("Expected get site/3.json and site/deep/4.json, but got %v", inputs)
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	type testcase struct {
+		pattern  string
+		name     string
+		expected bool
+	}
+
+	testcases := []testcase{
+		{pattern: "*.json", name: "site/device.json", expected: true},
+		{pattern: ".*", name: "site/.gitkeep", expected: true},
+		{pattern: "site/*.json", name: "site/device.json", expected: true},
+		{pattern: "site/*.json", name: "site/deep/device.json", expected: false},
+		{pattern: "site/**/*.json", name: "site/device.json", expected: true},
+		{pattern: "site/**/*.json", name: "site/a/b/device.json", expected: true},
+		{pattern: "**/deep", name: "site/deep", expected: true},
+		{pattern: "site/**", name: "other/device.json", expected: false},
+	}
+
+	for _, tc := range testcases {
+		result := matchGlob(tc.pattern, tc.name)
+		if result != tc.expected {
+			t.Errorf("Expected get %v of %s %s, but got %v", tc.expected, tc.pattern, tc.name, result)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package reader
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/lineprotocol"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+var (
+	DefaultLineProtocolMeasurement = "measurements"
+	DefaultLineProtocolField       = "metric_value"
+	DefaultLineProtocolDeviceTags  = []string{"device"}
+)
+
+// options of line protocol reader
+type LineProtocolOptions struct {
+	// only points of this measurement are read
+	Measurement string
+	// field holding metric value in bytes per second
+	Field string
+	// tags whose values (joined by "_") form the device that point belong to, empty means every file is one device
+	DeviceTags []string
+	Precision  lineprotocol.Precision
+}
+
+func DefaultLineProtocolOptions() LineProtocolOptions {
+	return LineProtocolOptions{
+		Measurement: DefaultLineProtocolMeasurement,
+		Field:       DefaultLineProtocolField,
+		DeviceTags:  DefaultLineProtocolDeviceTags,
+		Precision:   lineprotocol.PrecisionNanosecond,
+	}
+}
+
+type lineProtocolReader struct {
+	basePath string
+	options  LineProtocolOptions
+}
+
+// function to make reader that read InfluxDB line protocol files under basePath and group points into series per device
+func NewLineProtocolReader(basePath string, options LineProtocolOptions) lineProtocolReader {
+	return lineProtocolReader{
+		basePath: basePath,
+		options:  options,
+	}
+}
+
+// Get series of every device found in files under directory, series are ordered by device
+func (r lineProtocolReader) GetInputs() ([]types.InputFormat, error) {
+	entries, err := os.ReadDir(r.basePath)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make(map[string][]types.Mesurement)
+	for _, entry := range entries {
+		// ignore directory and hidden file
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		err := r.readFile(entry.Name(), series)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	devices := make([]string, 0, len(series))
+	for device := range series {
+		devices = append(devices, device)
+	}
+	sort.Strings(devices)
+
+	result := make([]types.InputFormat, 0, len(devices))
+	for _, device := range devices {
+		content := series[device]
+		sort.SliceStable(content, func(i, j int) bool {
+			return content[i].Dtime.Before(content[j].Dtime.Time)
+		})
+
+		result = append(result, types.InputFormat{
+			Name:    device,
+			Content: content,
+		})
+	}
+
+	return result, nil
+}
+
+// Get series of device
+func (r lineProtocolReader) GetInput(name string) (types.InputFormat, error) {
+	inputs, err := r.GetInputs()
+	if err != nil {
+		return types.InputFormat{}, err
+	}
+
+	for _, input := range inputs {
+		if input.Name == name {
+			return input, nil
+		}
+	}
+
+	return types.InputFormat{}, fmt.Errorf("device %s not found in %s", name, r.basePath)
+}
+
+// function to parse file and append its points to series of their device
+func (r lineProtocolReader) readFile(name string, series map[string][]types.Mesurement) error {
+	content, err := os.ReadFile(filepath.Join(r.basePath, name))
+	if err != nil {
+		return err
+	}
+
+	points, err := lineprotocol.Parse(content, r.options.Precision)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	for _, point := range points {
+		if point.Measurement != r.options.Measurement {
+			continue
+		}
+		value, ok := point.Float(r.options.Field)
+		if !ok {
+			continue
+		}
+		if point.Time.IsZero() {
+			return fmt.Errorf("%s: point of %s has no timestamp", name, point.Measurement)
+		}
+
+		device := strings.TrimSuffix(name, filepath.Ext(name))
+		if len(r.options.DeviceTags) > 0 {
+			values := make([]string, 0, len(r.options.DeviceTags))
+			for _, key := range r.options.DeviceTags {
+				value, ok := point.Tag(key)
+				if !ok {
+					value = "unknown"
+				}
+				values = append(values, value)
+			}
+			device = strings.Join(values, "_")
+		}
+
+		series[device] = append(series[device], types.Mesurement{
+			MetricValue: value,
+			// timestamp is an instant, so it is converted to the analysis timezone instead of keeping its wall clock
+			Dtime: types.JSONTime{
+				Time: point.Time,
+			},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package reader
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/lineprotocol"
+)
+
+func TestLineProtocolReader(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "a.lp"), []byte(`# speed tests
+measurements,device=2,region=eu metric_value=30 1609545600
+measurements,device=1,region=eu metric_value=20i 1609545600
+measurements,device=1,region=eu metric_value=10 1609459200
+latency,device=1 rtt=12 1609459200
+`), 0644)
+	os.WriteFile(filepath.Join(dir, "b.lp"), []byte(`measurements,device=1,region=us metric_value=40 1609632000
+measurements,region=us metric_value=50 1609632000
+`), 0644)
+
+	type testcase struct {
+		name     string
+		tags     []string
+		expected map[string][]float64
+	}
+
+	testcases := []testcase{
+		{
+			name: "Device tag",
+			tags: []string{"device"},
+			expected: map[string][]float64{
+				"1":       {10, 20, 40},
+				"2":       {30},
+				"unknown": {50},
+			},
+		},
+		{
+			name: "Device and region tag",
+			tags: []string{"device", "region"},
+			expected: map[string][]float64{
+				"1_eu":       {10, 20},
+				"1_us":       {40},
+				"2_eu":       {30},
+				"unknown_us": {50},
+			},
+		},
+		{
+			name: "File",
+			expected: map[string][]float64{
+				"a": {10, 30, 20},
+				"b": {40, 50},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := DefaultLineProtocolOptions()
+			options.DeviceTags = tc.tags
+			options.Precision = lineprotocol.PrecisionSecond
+
+			inputs, err := NewLineProtocolReader(dir, options).GetInputs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(inputs) != len(tc.expected) {
+				t.Fatalf("Expected get %d device, but got %d", len(tc.expected), len(inputs))
+			}
+			for _, input := range inputs {
+				values := make([]float64, 0)
+				for _, mesurement := range input.Content {
+					values = append(values, mesurement.MetricValue)
+				}
+				expected := tc.expected[input.Name]
+				if len(values) != len(expected) {
+					t.Errorf("Expected get %v, but got %v", expected, values)
+					continue
+				}
+				for i := range values {
+					if values[i] != expected[i] {
+						t.Errorf("Expected get %v, but got %v", expected, values)
+						break
+					}
+				}
+			}
+		})
+	}
+
+	_, err := NewLineProtocolReader(dir, DefaultLineProtocolOptions()).GetInput("3")
+	if err == nil {
+		t.Errorf("Expected get error, but got nil")
+	}
+
+	os.WriteFile(filepath.Join(dir, "c.lp"), []byte("measurements,device=3 metric_value=1\n"), 0644)
+	_, err = NewLineProtocolReader(dir, DefaultLineProtocolOptions()).GetInputs()
+	if err == nil {
+		t.Errorf("Expected get error for point without timestamp, but got nil")
+	}
+}
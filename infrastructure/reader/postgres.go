@@ -0,0 +1,185 @@
+package reader
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+var (
+	// query of devices that have mesurement in range, $1 = from (inclusive), $2 = to (exclusive)
+	DefaultPostgresDevicesQuery = `SELECT DISTINCT device FROM {{.Table}} WHERE dtime >= $1 AND dtime < $2 ORDER BY device`
+	// query of (dtime, metric_value) rows of one device in range ordered by time, $1 = device, $2 = from (inclusive), $3 = to (exclusive)
+	DefaultPostgresMesurementsQuery = `SELECT dtime, metric_value FROM {{.Table}} WHERE device = $1 AND dtime >= $2 AND dtime < $3 ORDER BY dtime`
+	DefaultPostgresTable            = "measurements"
+	// rows read for one device, more is rejected instead of keeping an unbounded series in memory
+	DefaultPostgresMaxMesurements = 10000000
+)
+
+// database type names (derived from OID of column by pgx) of time columns without UTC offset, pgx return their wall clock time as UTC
+var postgresWallClockTypes = map[string]bool{
+	"DATE":      true,
+	"TIMESTAMP": true,
+}
+
+// options of postgres reader, query templates are rendered with the options so {{.Table}} can be used
+type PostgresOptions struct {
+	Table            string
+	DevicesQuery     string
+	MesurementsQuery string
+	// range of mesurement that is read, zero value means unbounded
+	From time.Time
+	To   time.Time
+	// maximum number of rows of one device, 0 means unlimited
+	MaxMesurements int
+}
+
+func DefaultPostgresOptions() PostgresOptions {
+	return PostgresOptions{
+		Table:            DefaultPostgresTable,
+		DevicesQuery:     DefaultPostgresDevicesQuery,
+		MesurementsQuery: DefaultPostgresMesurementsQuery,
+		MaxMesurements:   DefaultPostgresMaxMesurements,
+	}
+}
+
+type postgresReader struct {
+	db               *sql.DB
+	devicesQuery     string
+	mesurementsQuery string
+	from             time.Time
+	to               time.Time
+	maxMesurements   int
+}
+
+// function to make reader that read series of every device from postgres (or TimescaleDB), device name is used as input name
+func NewPostgresReader(db *sql.DB, options PostgresOptions) (postgresReader, error) {
+	devicesQuery, err := renderQuery(options.DevicesQuery, options)
+	if err != nil {
+		return postgresReader{}, fmt.Errorf("devices query: %w", err)
+	}
+	mesurementsQuery, err := renderQuery(options.MesurementsQuery, options)
+	if err != nil {
+		return postgresReader{}, fmt.Errorf("mesurements query: %w", err)
+	}
+
+	from := options.From
+	if from.IsZero() {
+		from = time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	to := options.To
+	if to.IsZero() {
+		to = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+	}
+
+	return postgresReader{
+		db:               db,
+		devicesQuery:     devicesQuery,
+		mesurementsQuery: mesurementsQuery,
+		from:             from,
+		to:               to,
+		maxMesurements:   options.MaxMesurements,
+	}, nil
+}
+
+func renderQuery(query string, options PostgresOptions) (string, error) {
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	err = tmpl.Execute(&sb, options)
+	if err != nil {
+		return "", err
+	}
+
+	return sb.String(), nil
+}
+
+// Get series of every device that has mesurement in range
+func (r postgresReader) GetInputs() ([]types.InputFormat, error) {
+	rows, err := r.db.Query(r.devicesQuery, r.from, r.to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]string, 0)
+	for rows.Next() {
+		var device string
+		err := rows.Scan(&device)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.InputFormat, 0, len(devices))
+	for _, device := range devices {
+		input, err := r.GetInput(device)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, input)
+	}
+
+	return result, nil
+}
+
+// Get series of device in range, rows are scanned one by one instead of loading the whole result first
+func (r postgresReader) GetInput(name string) (types.InputFormat, error) {
+	rows, err := r.db.Query(r.mesurementsQuery, name, r.from, r.to)
+	if err != nil {
+		return types.InputFormat{}, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return types.InputFormat{}, fmt.Errorf("device %s: %w", name, err)
+	}
+	// timestamptz is an instant, so it is converted to the analysis timezone
+	// date and timestamp keep their wall clock time (e.g. day of daily mesurement) whatever the analysis timezone is
+	wallClock := len(columns) > 0 && postgresWallClockTypes[columns[0].DatabaseTypeName()]
+
+	mesurements := make([]types.Mesurement, 0)
+	for rows.Next() {
+		if r.maxMesurements > 0 && len(mesurements) >= r.maxMesurements {
+			return types.InputFormat{}, fmt.Errorf("device %s has more than %d mesurements in range (narrow the range or raise the maximum)", name, r.maxMesurements)
+		}
+
+		var dtime time.Time
+		var metricValue float64
+		err := rows.Scan(&dtime, &metricValue)
+		if err != nil {
+			return types.InputFormat{}, fmt.Errorf("device %s: %w", name, err)
+		}
+
+		mesurements = append(mesurements, types.Mesurement{
+			MetricValue: metricValue,
+			Dtime: types.JSONTime{
+				Time:      dtime,
+				WallClock: wallClock,
+			},
+		})
+	}
+	err = rows.Err()
+	if err != nil {
+		return types.InputFormat{}, fmt.Errorf("device %s: %w", name, err)
+	}
+
+	return types.InputFormat{
+		Name:    name,
+		Content: mesurements,
+	}, nil
+}
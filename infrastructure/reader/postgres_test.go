@@ -0,0 +1,349 @@
+package reader
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+type stubRow struct {
+	device string
+	dtime  time.Time
+	value  float64
+}
+
+// SQL driver stub that answer devices query (2 arguments) and mesurements query (3 arguments) from rows, and remember executed queries
+// database type name of dtime column is dtimeType (TIMESTAMPTZ when empty)
+type stubDriver struct {
+	mu        sync.Mutex
+	rows      []stubRow
+	queries   []string
+	dtimeType string
+}
+
+func (d *stubDriver) Open(name string) (driver.Conn, error) {
+	return stubConn{driver: d}, nil
+}
+
+// connector so driver can be used with sql.OpenDB without registering it
+func (d *stubDriver) Connect(ctx context.Context) (driver.Conn, error) {
+	return d.Open("")
+}
+
+func (d *stubDriver) Driver() driver.Driver {
+	return d
+}
+
+type stubConn struct {
+	driver *stubDriver
+}
+
+func (c stubConn) Prepare(query string) (driver.Stmt, error) {
+	c.driver.mu.Lock()
+	c.driver.queries = append(c.driver.queries, query)
+	c.driver.mu.Unlock()
+	return stubStmt{driver: c.driver}, nil
+}
+
+func (c stubConn) Close() error              { return nil }
+func (c stubConn) Begin() (driver.Tx, error) { return nil, driver.ErrSkip }
+
+type stubStmt struct {
+	driver *stubDriver
+}
+
+func (s stubStmt) Close() error  { return nil }
+func (s stubStmt) NumInput() int { return -1 }
+func (s stubStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, driver.ErrSkip
+}
+
+func (s stubStmt) Query(args []driver.Value) (driver.Rows, error) {
+	from, to := args[len(args)-2].(time.Time), args[len(args)-1].(time.Time)
+	inRange := func(row stubRow) bool {
+		return !row.dtime.Before(from) && row.dtime.Before(to)
+	}
+
+	result := &stubRows{types: []string{s.driver.dtimeType, "FLOAT8"}}
+	if result.types[0] == "" {
+		result.types[0] = "TIMESTAMPTZ"
+	}
+	if len(args) == 2 {
+		result.columns = []string{"device"}
+		seen := make(map[string]bool)
+		for _, row := range s.driver.rows {
+			if inRange(row) && !seen[row.device] {
+				seen[row.device] = true
+				result.values = append(result.values, []driver.Value{row.device})
+			}
+		}
+		sort.Slice(result.values, func(i, j int) bool {
+			return result.values[i][0].(string) < result.values[j][0].(string)
+		})
+		return result, nil
+	}
+
+	result.columns = []string{"dtime", "metric_value"}
+	for _, row := range s.driver.rows {
+		if row.device == args[0].(string) && inRange(row) {
+			result.values = append(result.values, []driver.Value{row.dtime, row.value})
+		}
+	}
+	return result, nil
+}
+
+type stubRows struct {
+	columns []string
+	types   []string
+	values  [][]driver.Value
+}
+
+func (r *stubRows) ColumnTypeDatabaseTypeName(index int) string { return r.types[index] }
+func (r *stubRows) Columns() []string                           { return r.columns }
+func (r *stubRows) Close() error                                { return nil }
+func (r *stubRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+var stub = &stubDriver{
+	rows: []stubRow{
+		{device: "device2", dtime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), value: 3},
+		{device: "device1", dtime: time.Date(2021, 1, 1, 18, 30, 0, 0, time.UTC), value: 1},
+		{device: "device1", dtime: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), value: 2},
+		{device: "device1", dtime: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), value: 4},
+	},
+}
+
+func init() {
+	sql.Register("stub", stub)
+}
+
+func TestPostgresReader(t *testing.T) {
+	type testcase struct {
+		name     string
+		options  PostgresOptions
+		expected map[string][]float64
+	}
+
+	january := DefaultPostgresOptions()
+	january.From = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	january.To = time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	testcases := []testcase{
+		{
+			name:    "Unbounded",
+			options: DefaultPostgresOptions(),
+			expected: map[string][]float64{
+				"device1": {1, 2, 4},
+				"device2": {3},
+			},
+		},
+		{
+			name:    "Range",
+			options: january,
+			expected: map[string][]float64{
+				"device1": {1},
+				"device2": {3},
+			},
+		},
+	}
+
+	db, err := sql.Open("stub", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := NewPostgresReader(db, tc.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			inputs, err := r.GetInputs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(inputs) != len(tc.expected) {
+				t.Fatalf("Expected get %d device, but got %d", len(tc.expected), len(inputs))
+			}
+			for _, input := range inputs {
+				values := make([]float64, 0)
+				for _, mesurement := range input.Content {
+					values = append(values, mesurement.MetricValue)
+				}
+				if len(values) != len(tc.expected[input.Name]) {
+					t.Errorf("Expected get %v, but got %v", tc.expected[input.Name], values)
+					continue
+				}
+				for i := range values {
+					if values[i] != tc.expected[input.Name][i] {
+						t.Errorf("Expected get %v, but got %v", tc.expected[input.Name], values)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresReaderQueryTemplate(t *testing.T) {
+	db, err := sql.Open("stub", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	options := DefaultPostgresOptions()
+	options.Table = "metrics.download"
+	options.MesurementsQuery = `SELECT time, value FROM {{.Table}} WHERE host = $1 AND time >= $2 AND time < $3 ORDER BY time`
+	r, err := NewPostgresReader(db, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.GetInput("device1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "SELECT time, value FROM metrics.download WHERE host = $1 AND time >= $2 AND time < $3 ORDER BY time"
+	stub.mu.Lock()
+	query := stub.queries[len(stub.queries)-1]
+	stub.mu.Unlock()
+	if query != expected {
+		t.Errorf("Expected get %s, but got %s", expected, query)
+	}
+
+	for _, template := range []string{"SELECT {{.Table", "SELECT {{.Unknown}}"} {
+		options.MesurementsQuery = template
+		_, err = NewPostgresReader(db, options)
+		if err == nil {
+			t.Errorf("Expected get error for %s, but got nil", template)
+		}
+	}
+}
+
+func TestPostgresReaderColumnType(t *testing.T) {
+	type testcase struct {
+		name      string
+		dtimeType string
+		wallClock bool
+		date      string
+	}
+
+	// 2021-01-01 00:00 is still 2020-12-31 in New York when it is an instant
+	testcases := []testcase{
+		{
+			name:      "Date",
+			dtimeType: "DATE",
+			wallClock: true,
+			date:      "2021-01-01",
+		},
+		{
+			name:      "Timestamp",
+			dtimeType: "TIMESTAMP",
+			wallClock: true,
+			date:      "2021-01-01",
+		},
+		{
+			name:      "Timestamptz",
+			dtimeType: "TIMESTAMPTZ",
+			wallClock: false,
+			date:      "2020-12-31",
+		},
+	}
+
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			db := sql.OpenDB(&stubDriver{
+				rows:      []stubRow{{device: "device1", dtime: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), value: 1}},
+				dtimeType: tc.dtimeType,
+			})
+			defer db.Close()
+
+			r, err := NewPostgresReader(db, DefaultPostgresOptions())
+			if err != nil {
+				t.Fatal(err)
+			}
+			input, err := r.GetInput("device1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(input.Content) != 1 {
+				t.Fatalf("Expected get 1 mesurement, but got %d", len(input.Content))
+			}
+
+			dtime := input.Content[0].Dtime
+			if dtime.WallClock != tc.wallClock {
+				t.Errorf("Expected get %v, but got %v", tc.wallClock, dtime.WallClock)
+			}
+			if date := dtime.Localise(newYork).Format("2006-01-02"); date != tc.date {
+				t.Errorf("Expected get %s, but got %s", tc.date, date)
+			}
+		})
+	}
+}
+
+func TestPostgresReaderMaxMesurements(t *testing.T) {
+	type testcase struct {
+		name           string
+		maxMesurements int
+		err            bool
+	}
+
+	// device1 has 3 mesurements
+	testcases := []testcase{
+		{
+			name:           "Unlimited",
+			maxMesurements: 0,
+		},
+		{
+			name:           "Within limit",
+			maxMesurements: 3,
+		},
+		{
+			name:           "Over limit",
+			maxMesurements: 2,
+			err:            true,
+		},
+	}
+
+	db, err := sql.Open("stub", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := DefaultPostgresOptions()
+			options.MaxMesurements = tc.maxMesurements
+			r, err := NewPostgresReader(db, options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = r.GetInput("device1")
+			if (err != nil) != tc.err {
+				t.Errorf("Expected error %v, but got %v", tc.err, err)
+			}
+		})
+	}
+}
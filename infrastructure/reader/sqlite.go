@@ -0,0 +1,93 @@
+package reader
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+type sqliteReader struct {
+	db *sql.DB
+}
+
+// function to make reader that read mesurement series of every device from measurements table (see sqlite.Open), dtime is Unix nanoseconds
+func NewSQLiteReader(db *sql.DB) sqliteReader {
+	return sqliteReader{
+		db: db,
+	}
+}
+
+// Get series of every device
+func (r sqliteReader) GetInputs() ([]types.InputFormat, error) {
+	rows, err := r.db.Query("SELECT DISTINCT device FROM measurements ORDER BY device")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := make([]string, 0)
+	for rows.Next() {
+		var device string
+		err := rows.Scan(&device)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]types.InputFormat, 0, len(devices))
+	for _, device := range devices {
+		input, err := r.GetInput(device)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, input)
+	}
+
+	return result, nil
+}
+
+// Get series of device ordered by time
+func (r sqliteReader) GetInput(name string) (types.InputFormat, error) {
+	rows, err := r.db.Query("SELECT dtime, wall_clock, metric_value FROM measurements WHERE device = ? ORDER BY dtime", name)
+	if err != nil {
+		return types.InputFormat{}, err
+	}
+	defer rows.Close()
+
+	mesurements := make([]types.Mesurement, 0)
+	for rows.Next() {
+		var dtime int64
+		var wallClock bool
+		var metricValue float64
+		err := rows.Scan(&dtime, &wallClock, &metricValue)
+		if err != nil {
+			return types.InputFormat{}, fmt.Errorf("device %s: %w", name, err)
+		}
+
+		mesurements = append(mesurements, types.Mesurement{
+			MetricValue: metricValue,
+			Dtime: types.JSONTime{
+				// Unix nanoseconds, wall clock time is stored as if it were UTC
+				Time:      time.Unix(0, dtime).UTC(),
+				WallClock: wallClock,
+			},
+		})
+	}
+	err = rows.Err()
+	if err != nil {
+		return types.InputFormat{}, err
+	}
+
+	return types.InputFormat{
+		Name:    name,
+		Content: mesurements,
+	}, nil
+}
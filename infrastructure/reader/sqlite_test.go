@@ -0,0 +1,53 @@
+package reader
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/sqlite"
+)
+
+func TestSQLiteReader(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "analyser.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`INSERT INTO measurements (device, dtime, wall_clock, metric_value) VALUES
+		('device2', ?, 1, 3),
+		('device1', ?, 1, 2),
+		('device1', ?, 0, 1)`,
+		time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano(),
+		time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC).UnixNano(),
+		time.Date(2021, 1, 1, 20, 30, 0, 0, time.FixedZone("", 2*60*60)).UnixNano())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inputs, err := NewSQLiteReader(db).GetInputs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) != 2 || inputs[0].Name != "device1" || inputs[1].Name != "device2" {
+		t.Fatalf("Expected get device1 and device2, but got %v", inputs)
+	}
+
+	content := inputs[0].Content
+	if len(content) != 2 {
+		t.Fatalf("Expected get 2 mesurement, but got %d", len(content))
+	}
+	// ordered by time
+	if content[0].MetricValue != 1 || !content[0].Dtime.Equal(time.Date(2021, 1, 1, 18, 30, 0, 0, time.UTC)) || content[0].Dtime.WallClock {
+		t.Errorf("Expected get 1 at 2021-01-01T18:30:00Z, but got %v at %v (wall clock %t)", content[0].MetricValue, content[0].Dtime.Time, content[0].Dtime.WallClock)
+	}
+	if content[1].MetricValue != 2 || !content[1].Dtime.WallClock {
+		t.Errorf("Expected get wall clock 2, but got %v (wall clock %t)", content[1].MetricValue, content[1].Dtime.WallClock)
+	}
+
+	input, err := NewSQLiteReader(db).GetInput("unknown")
+	if err != nil || len(input.Content) != 0 {
+		t.Errorf("Expected get empty series, but got %v (%v)", input.Content, err)
+	}
+}
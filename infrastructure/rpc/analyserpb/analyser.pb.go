@@ -0,0 +1,920 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v4.25.3
+// source: analyser.proto
+
+package analyserpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Format int32
+
+const (
+	// Structured report only.
+	Format_FORMAT_UNSPECIFIED Format = 0
+	Format_FORMAT_TEXT        Format = 1
+	Format_FORMAT_JSON        Format = 2
+	Format_FORMAT_HTML        Format = 3
+	Format_FORMAT_MARKDOWN    Format = 4
+	// InfluxDB line protocol points.
+	Format_FORMAT_LINE_PROTOCOL Format = 5
+)
+
+// Enum value maps for Format.
+var (
+	Format_name = map[int32]string{
+		0: "FORMAT_UNSPECIFIED",
+		1: "FORMAT_TEXT",
+		2: "FORMAT_JSON",
+		3: "FORMAT_HTML",
+		4: "FORMAT_MARKDOWN",
+		5: "FORMAT_LINE_PROTOCOL",
+	}
+	Format_value = map[string]int32{
+		"FORMAT_UNSPECIFIED":   0,
+		"FORMAT_TEXT":          1,
+		"FORMAT_JSON":          2,
+		"FORMAT_HTML":          3,
+		"FORMAT_MARKDOWN":      4,
+		"FORMAT_LINE_PROTOCOL": 5,
+	}
+)
+
+func (x Format) Enum() *Format {
+	p := new(Format)
+	*p = x
+	return p
+}
+
+func (x Format) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Format) Descriptor() protoreflect.EnumDescriptor {
+	return file_analyser_proto_enumTypes[0].Descriptor()
+}
+
+func (Format) Type() protoreflect.EnumType {
+	return &file_analyser_proto_enumTypes[0]
+}
+
+func (x Format) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Format.Descriptor instead.
+func (Format) EnumDescriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{0}
+}
+
+type Measurement struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Bytes per second.
+	MetricValue float64                `protobuf:"fixed64,1,opt,name=metric_value,json=metricValue,proto3" json:"metric_value,omitempty"`
+	Dtime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=dtime,proto3" json:"dtime,omitempty"`
+	// True when dtime has no UTC offset in source (e.g. date only) and is wall clock time of the timezone it is analysed in.
+	WallClock     bool `protobuf:"varint,3,opt,name=wall_clock,json=wallClock,proto3" json:"wall_clock,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Measurement) Reset() {
+	*x = Measurement{}
+	mi := &file_analyser_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Measurement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Measurement) ProtoMessage() {}
+
+func (x *Measurement) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Measurement.ProtoReflect.Descriptor instead.
+func (*Measurement) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Measurement) GetMetricValue() float64 {
+	if x != nil {
+		return x.MetricValue
+	}
+	return 0
+}
+
+func (x *Measurement) GetDtime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Dtime
+	}
+	return nil
+}
+
+func (x *Measurement) GetWallClock() bool {
+	if x != nil {
+		return x.WallClock
+	}
+	return false
+}
+
+type AnalyseRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of the series, used to look up per input timezone.
+	Name         string         `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Measurements []*Measurement `protobuf:"bytes,2,rep,name=measurements,proto3" json:"measurements,omitempty"`
+	// Format of the rendered report returned besides the structured report.
+	Format        Format `protobuf:"varint,3,opt,name=format,proto3,enum=analyser.v1.Format" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyseRequest) Reset() {
+	*x = AnalyseRequest{}
+	mi := &file_analyser_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyseRequest) ProtoMessage() {}
+
+func (x *AnalyseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyseRequest.ProtoReflect.Descriptor instead.
+func (*AnalyseRequest) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *AnalyseRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AnalyseRequest) GetMeasurements() []*Measurement {
+	if x != nil {
+		return x.Measurements
+	}
+	return nil
+}
+
+func (x *AnalyseRequest) GetFormat() Format {
+	if x != nil {
+		return x.Format
+	}
+	return Format_FORMAT_UNSPECIFIED
+}
+
+// Values are in bytes per second.
+type Statistics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	Min           float64                `protobuf:"fixed64,2,opt,name=min,proto3" json:"min,omitempty"`
+	Max           float64                `protobuf:"fixed64,3,opt,name=max,proto3" json:"max,omitempty"`
+	Mean          float64                `protobuf:"fixed64,4,opt,name=mean,proto3" json:"mean,omitempty"`
+	Median        float64                `protobuf:"fixed64,5,opt,name=median,proto3" json:"median,omitempty"`
+	FirstQuartile float64                `protobuf:"fixed64,6,opt,name=first_quartile,json=firstQuartile,proto3" json:"first_quartile,omitempty"`
+	Iqr           float64                `protobuf:"fixed64,7,opt,name=iqr,proto3" json:"iqr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statistics) Reset() {
+	*x = Statistics{}
+	mi := &file_analyser_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statistics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statistics) ProtoMessage() {}
+
+func (x *Statistics) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statistics.ProtoReflect.Descriptor instead.
+func (*Statistics) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Statistics) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Statistics) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *Statistics) GetMax() float64 {
+	if x != nil {
+		return x.Max
+	}
+	return 0
+}
+
+func (x *Statistics) GetMean() float64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+func (x *Statistics) GetMedian() float64 {
+	if x != nil {
+		return x.Median
+	}
+	return 0
+}
+
+func (x *Statistics) GetFirstQuartile() float64 {
+	if x != nil {
+		return x.FirstQuartile
+	}
+	return 0
+}
+
+func (x *Statistics) GetIqr() float64 {
+	if x != nil {
+		return x.Iqr
+	}
+	return 0
+}
+
+type Bucket struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	Label                string                 `protobuf:"bytes,1,opt,name=label,proto3" json:"label,omitempty"`
+	Statistics           *Statistics            `protobuf:"bytes,2,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	UnderPerformingCount int64                  `protobuf:"varint,3,opt,name=under_performing_count,json=underPerformingCount,proto3" json:"under_performing_count,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Bucket) Reset() {
+	*x = Bucket{}
+	mi := &file_analyser_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bucket) ProtoMessage() {}
+
+func (x *Bucket) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bucket.ProtoReflect.Descriptor instead.
+func (*Bucket) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Bucket) GetLabel() string {
+	if x != nil {
+		return x.Label
+	}
+	return ""
+}
+
+func (x *Bucket) GetStatistics() *Statistics {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+func (x *Bucket) GetUnderPerformingCount() int64 {
+	if x != nil {
+		return x.UnderPerformingCount
+	}
+	return 0
+}
+
+type Aggregation struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Buckets       []*Bucket              `protobuf:"bytes,2,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Aggregation) Reset() {
+	*x = Aggregation{}
+	mi := &file_analyser_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Aggregation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Aggregation) ProtoMessage() {}
+
+func (x *Aggregation) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Aggregation.ProtoReflect.Descriptor instead.
+func (*Aggregation) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Aggregation) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Aggregation) GetBuckets() []*Bucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+type PeakAnalysis struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Window    string                 `protobuf:"bytes,1,opt,name=window,proto3" json:"window,omitempty"`
+	Timezone  string                 `protobuf:"bytes,2,opt,name=timezone,proto3" json:"timezone,omitempty"`
+	Peak      *Statistics            `protobuf:"bytes,3,opt,name=peak,proto3" json:"peak,omitempty"`
+	OffPeak   *Statistics            `protobuf:"bytes,4,opt,name=off_peak,json=offPeak,proto3" json:"off_peak,omitempty"`
+	Threshold float64                `protobuf:"fixed64,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	// Peak average divided by off-peak average, only meaningful when ratio_available (0 is a complete outage during peak hours).
+	Ratio           float64 `protobuf:"fixed64,6,opt,name=ratio,proto3" json:"ratio,omitempty"`
+	UnderPerforming bool    `protobuf:"varint,7,opt,name=under_performing,json=underPerforming,proto3" json:"under_performing,omitempty"`
+	// False when either side has no measurement or off-peak average is 0.
+	RatioAvailable bool `protobuf:"varint,8,opt,name=ratio_available,json=ratioAvailable,proto3" json:"ratio_available,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PeakAnalysis) Reset() {
+	*x = PeakAnalysis{}
+	mi := &file_analyser_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PeakAnalysis) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PeakAnalysis) ProtoMessage() {}
+
+func (x *PeakAnalysis) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PeakAnalysis.ProtoReflect.Descriptor instead.
+func (*PeakAnalysis) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PeakAnalysis) GetWindow() string {
+	if x != nil {
+		return x.Window
+	}
+	return ""
+}
+
+func (x *PeakAnalysis) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *PeakAnalysis) GetPeak() *Statistics {
+	if x != nil {
+		return x.Peak
+	}
+	return nil
+}
+
+func (x *PeakAnalysis) GetOffPeak() *Statistics {
+	if x != nil {
+		return x.OffPeak
+	}
+	return nil
+}
+
+func (x *PeakAnalysis) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *PeakAnalysis) GetRatio() float64 {
+	if x != nil {
+		return x.Ratio
+	}
+	return 0
+}
+
+func (x *PeakAnalysis) GetUnderPerforming() bool {
+	if x != nil {
+		return x.UnderPerforming
+	}
+	return false
+}
+
+func (x *PeakAnalysis) GetRatioAvailable() bool {
+	if x != nil {
+		return x.RatioAvailable
+	}
+	return false
+}
+
+// Continuous range of under-performing dates.
+type Period struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Start of the first and the last date of the period.
+	From *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Number of calendar dates in the period.
+	Days          int64 `protobuf:"varint,3,opt,name=days,proto3" json:"days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Period) Reset() {
+	*x = Period{}
+	mi := &file_analyser_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Period) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Period) ProtoMessage() {}
+
+func (x *Period) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Period.ProtoReflect.Descriptor instead.
+func (*Period) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Period) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Period) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Period) GetDays() int64 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+type Report struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Name                   string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	From                   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To                     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Unit                   string                 `protobuf:"bytes,4,opt,name=unit,proto3" json:"unit,omitempty"`
+	UnitExponent           int32                  `protobuf:"varint,5,opt,name=unit_exponent,json=unitExponent,proto3" json:"unit_exponent,omitempty"`
+	Statistics             *Statistics            `protobuf:"bytes,6,opt,name=statistics,proto3" json:"statistics,omitempty"`
+	UnderPerformingPeriods []string               `protobuf:"bytes,7,rep,name=under_performing_periods,json=underPerformingPeriods,proto3" json:"under_performing_periods,omitempty"`
+	Aggregations           []*Aggregation         `protobuf:"bytes,8,rep,name=aggregations,proto3" json:"aggregations,omitempty"`
+	// Unset when peak hours analysis is disabled.
+	Peak *PeakAnalysis `protobuf:"bytes,9,opt,name=peak,proto3" json:"peak,omitempty"`
+	// Distinct days in under_performing_periods.
+	UnderPerformingDays int64 `protobuf:"varint,10,opt,name=under_performing_days,json=underPerformingDays,proto3" json:"under_performing_days,omitempty"`
+	// The same periods as under_performing_periods.
+	UnderPerformingRanges []*Period `protobuf:"bytes,11,rep,name=under_performing_ranges,json=underPerformingRanges,proto3" json:"under_performing_ranges,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Report) Reset() {
+	*x = Report{}
+	mi := &file_analyser_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Report) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Report) ProtoMessage() {}
+
+func (x *Report) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Report.ProtoReflect.Descriptor instead.
+func (*Report) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Report) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Report) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *Report) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *Report) GetUnit() string {
+	if x != nil {
+		return x.Unit
+	}
+	return ""
+}
+
+func (x *Report) GetUnitExponent() int32 {
+	if x != nil {
+		return x.UnitExponent
+	}
+	return 0
+}
+
+func (x *Report) GetStatistics() *Statistics {
+	if x != nil {
+		return x.Statistics
+	}
+	return nil
+}
+
+func (x *Report) GetUnderPerformingPeriods() []string {
+	if x != nil {
+		return x.UnderPerformingPeriods
+	}
+	return nil
+}
+
+func (x *Report) GetAggregations() []*Aggregation {
+	if x != nil {
+		return x.Aggregations
+	}
+	return nil
+}
+
+func (x *Report) GetPeak() *PeakAnalysis {
+	if x != nil {
+		return x.Peak
+	}
+	return nil
+}
+
+func (x *Report) GetUnderPerformingDays() int64 {
+	if x != nil {
+		return x.UnderPerformingDays
+	}
+	return 0
+}
+
+func (x *Report) GetUnderPerformingRanges() []*Period {
+	if x != nil {
+		return x.UnderPerformingRanges
+	}
+	return nil
+}
+
+type AnalyseResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Report *Report                `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	// Report rendered in the requested format, empty for FORMAT_UNSPECIFIED.
+	Rendered      []byte `protobuf:"bytes,2,opt,name=rendered,proto3" json:"rendered,omitempty"`
+	ContentType   string `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyseResponse) Reset() {
+	*x = AnalyseResponse{}
+	mi := &file_analyser_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyseResponse) ProtoMessage() {}
+
+func (x *AnalyseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_analyser_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyseResponse.ProtoReflect.Descriptor instead.
+func (*AnalyseResponse) Descriptor() ([]byte, []int) {
+	return file_analyser_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AnalyseResponse) GetReport() *Report {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+func (x *AnalyseResponse) GetRendered() []byte {
+	if x != nil {
+		return x.Rendered
+	}
+	return nil
+}
+
+func (x *AnalyseResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+var File_analyser_proto protoreflect.FileDescriptor
+
+const file_analyser_proto_rawDesc = "" +
+	"\n" +
+	"\x0eanalyser.proto\x12\vanalyser.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\x81\x01\n" +
+	"\vMeasurement\x12!\n" +
+	"\fmetric_value\x18\x01 \x01(\x01R\vmetricValue\x120\n" +
+	"\x05dtime\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05dtime\x12\x1d\n" +
+	"\n" +
+	"wall_clock\x18\x03 \x01(\bR\twallClock\"\x8f\x01\n" +
+	"\x0eAnalyseRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12<\n" +
+	"\fmeasurements\x18\x02 \x03(\v2\x18.analyser.v1.MeasurementR\fmeasurements\x12+\n" +
+	"\x06format\x18\x03 \x01(\x0e2\x13.analyser.v1.FormatR\x06format\"\xab\x01\n" +
+	"\n" +
+	"Statistics\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x10\n" +
+	"\x03min\x18\x02 \x01(\x01R\x03min\x12\x10\n" +
+	"\x03max\x18\x03 \x01(\x01R\x03max\x12\x12\n" +
+	"\x04mean\x18\x04 \x01(\x01R\x04mean\x12\x16\n" +
+	"\x06median\x18\x05 \x01(\x01R\x06median\x12%\n" +
+	"\x0efirst_quartile\x18\x06 \x01(\x01R\rfirstQuartile\x12\x10\n" +
+	"\x03iqr\x18\a \x01(\x01R\x03iqr\"\x8d\x01\n" +
+	"\x06Bucket\x12\x14\n" +
+	"\x05label\x18\x01 \x01(\tR\x05label\x127\n" +
+	"\n" +
+	"statistics\x18\x02 \x01(\v2\x17.analyser.v1.StatisticsR\n" +
+	"statistics\x124\n" +
+	"\x16under_performing_count\x18\x03 \x01(\x03R\x14underPerformingCount\"P\n" +
+	"\vAggregation\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\abuckets\x18\x02 \x03(\v2\x13.analyser.v1.BucketR\abuckets\"\xab\x02\n" +
+	"\fPeakAnalysis\x12\x16\n" +
+	"\x06window\x18\x01 \x01(\tR\x06window\x12\x1a\n" +
+	"\btimezone\x18\x02 \x01(\tR\btimezone\x12+\n" +
+	"\x04peak\x18\x03 \x01(\v2\x17.analyser.v1.StatisticsR\x04peak\x122\n" +
+	"\boff_peak\x18\x04 \x01(\v2\x17.analyser.v1.StatisticsR\aoffPeak\x12\x1c\n" +
+	"\tthreshold\x18\x05 \x01(\x01R\tthreshold\x12\x14\n" +
+	"\x05ratio\x18\x06 \x01(\x01R\x05ratio\x12)\n" +
+	"\x10under_performing\x18\a \x01(\bR\x0funderPerforming\x12'\n" +
+	"\x0fratio_available\x18\b \x01(\bR\x0eratioAvailable\"x\n" +
+	"\x06Period\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x12\n" +
+	"\x04days\x18\x03 \x01(\x03R\x04days\"\x92\x04\n" +
+	"\x06Report\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12.\n" +
+	"\x04from\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x12\n" +
+	"\x04unit\x18\x04 \x01(\tR\x04unit\x12#\n" +
+	"\runit_exponent\x18\x05 \x01(\x05R\funitExponent\x127\n" +
+	"\n" +
+	"statistics\x18\x06 \x01(\v2\x17.analyser.v1.StatisticsR\n" +
+	"statistics\x128\n" +
+	"\x18under_performing_periods\x18\a \x03(\tR\x16underPerformingPeriods\x12<\n" +
+	"\faggregations\x18\b \x03(\v2\x18.analyser.v1.AggregationR\faggregations\x12-\n" +
+	"\x04peak\x18\t \x01(\v2\x19.analyser.v1.PeakAnalysisR\x04peak\x122\n" +
+	"\x15under_performing_days\x18\n" +
+	" \x01(\x03R\x13underPerformingDays\x12K\n" +
+	"\x17under_performing_ranges\x18\v \x03(\v2\x13.analyser.v1.PeriodR\x15underPerformingRanges\"}\n" +
+	"\x0fAnalyseResponse\x12+\n" +
+	"\x06report\x18\x01 \x01(\v2\x13.analyser.v1.ReportR\x06report\x12\x1a\n" +
+	"\brendered\x18\x02 \x01(\fR\brendered\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType*\x82\x01\n" +
+	"\x06Format\x12\x16\n" +
+	"\x12FORMAT_UNSPECIFIED\x10\x00\x12\x0f\n" +
+	"\vFORMAT_TEXT\x10\x01\x12\x0f\n" +
+	"\vFORMAT_JSON\x10\x02\x12\x0f\n" +
+	"\vFORMAT_HTML\x10\x03\x12\x13\n" +
+	"\x0fFORMAT_MARKDOWN\x10\x04\x12\x18\n" +
+	"\x14FORMAT_LINE_PROTOCOL\x10\x052\xa5\x01\n" +
+	"\x0fAnalyserService\x12D\n" +
+	"\aAnalyse\x12\x1b.analyser.v1.AnalyseRequest\x1a\x1c.analyser.v1.AnalyseResponse\x12L\n" +
+	"\rAnalyseStream\x12\x1b.analyser.v1.AnalyseRequest\x1a\x1c.analyser.v1.AnalyseResponse(\x01BMZKgithub.com/awcjack/samknows-backend-code-test/infrastructure/rpc/analyserpbb\x06proto3"
+
+var (
+	file_analyser_proto_rawDescOnce sync.Once
+	file_analyser_proto_rawDescData []byte
+)
+
+func file_analyser_proto_rawDescGZIP() []byte {
+	file_analyser_proto_rawDescOnce.Do(func() {
+		file_analyser_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_analyser_proto_rawDesc), len(file_analyser_proto_rawDesc)))
+	})
+	return file_analyser_proto_rawDescData
+}
+
+var file_analyser_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_analyser_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_analyser_proto_goTypes = []any{
+	(Format)(0),                   // 0: analyser.v1.Format
+	(*Measurement)(nil),           // 1: analyser.v1.Measurement
+	(*AnalyseRequest)(nil),        // 2: analyser.v1.AnalyseRequest
+	(*Statistics)(nil),            // 3: analyser.v1.Statistics
+	(*Bucket)(nil),                // 4: analyser.v1.Bucket
+	(*Aggregation)(nil),           // 5: analyser.v1.Aggregation
+	(*PeakAnalysis)(nil),          // 6: analyser.v1.PeakAnalysis
+	(*Period)(nil),                // 7: analyser.v1.Period
+	(*Report)(nil),                // 8: analyser.v1.Report
+	(*AnalyseResponse)(nil),       // 9: analyser.v1.AnalyseResponse
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_analyser_proto_depIdxs = []int32{
+	10, // 0: analyser.v1.Measurement.dtime:type_name -> google.protobuf.Timestamp
+	1,  // 1: analyser.v1.AnalyseRequest.measurements:type_name -> analyser.v1.Measurement
+	0,  // 2: analyser.v1.AnalyseRequest.format:type_name -> analyser.v1.Format
+	3,  // 3: analyser.v1.Bucket.statistics:type_name -> analyser.v1.Statistics
+	4,  // 4: analyser.v1.Aggregation.buckets:type_name -> analyser.v1.Bucket
+	3,  // 5: analyser.v1.PeakAnalysis.peak:type_name -> analyser.v1.Statistics
+	3,  // 6: analyser.v1.PeakAnalysis.off_peak:type_name -> analyser.v1.Statistics
+	10, // 7: analyser.v1.Period.from:type_name -> google.protobuf.Timestamp
+	10, // 8: analyser.v1.Period.to:type_name -> google.protobuf.Timestamp
+	10, // 9: analyser.v1.Report.from:type_name -> google.protobuf.Timestamp
+	10, // 10: analyser.v1.Report.to:type_name -> google.protobuf.Timestamp
+	3,  // 11: analyser.v1.Report.statistics:type_name -> analyser.v1.Statistics
+	5,  // 12: analyser.v1.Report.aggregations:type_name -> analyser.v1.Aggregation
+	6,  // 13: analyser.v1.Report.peak:type_name -> analyser.v1.PeakAnalysis
+	7,  // 14: analyser.v1.Report.under_performing_ranges:type_name -> analyser.v1.Period
+	8,  // 15: analyser.v1.AnalyseResponse.report:type_name -> analyser.v1.Report
+	2,  // 16: analyser.v1.AnalyserService.Analyse:input_type -> analyser.v1.AnalyseRequest
+	2,  // 17: analyser.v1.AnalyserService.AnalyseStream:input_type -> analyser.v1.AnalyseRequest
+	9,  // 18: analyser.v1.AnalyserService.Analyse:output_type -> analyser.v1.AnalyseResponse
+	9,  // 19: analyser.v1.AnalyserService.AnalyseStream:output_type -> analyser.v1.AnalyseResponse
+	18, // [18:20] is the sub-list for method output_type
+	16, // [16:18] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_analyser_proto_init() }
+func file_analyser_proto_init() {
+	if File_analyser_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_analyser_proto_rawDesc), len(file_analyser_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_analyser_proto_goTypes,
+		DependencyIndexes: file_analyser_proto_depIdxs,
+		EnumInfos:         file_analyser_proto_enumTypes,
+		MessageInfos:      file_analyser_proto_msgTypes,
+	}.Build()
+	File_analyser_proto = out.File
+	file_analyser_proto_goTypes = nil
+	file_analyser_proto_depIdxs = nil
+}
@@ -0,0 +1,161 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v4.25.3
+// source: analyser.proto
+
+package analyserpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AnalyserService_Analyse_FullMethodName       = "/analyser.v1.AnalyserService/Analyse"
+	AnalyserService_AnalyseStream_FullMethodName = "/analyser.v1.AnalyserService/AnalyseStream"
+)
+
+// AnalyserServiceClient is the client API for AnalyserService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Analyse download performance series with the same statistics as the cli report.
+type AnalyserServiceClient interface {
+	// Analyse a whole series in one request.
+	Analyse(ctx context.Context, in *AnalyseRequest, opts ...grpc.CallOption) (*AnalyseResponse, error)
+	// Push a series in chunks, name and format are taken from the first message that set them.
+	AnalyseStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AnalyseRequest, AnalyseResponse], error)
+}
+
+type analyserServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAnalyserServiceClient(cc grpc.ClientConnInterface) AnalyserServiceClient {
+	return &analyserServiceClient{cc}
+}
+
+func (c *analyserServiceClient) Analyse(ctx context.Context, in *AnalyseRequest, opts ...grpc.CallOption) (*AnalyseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyseResponse)
+	err := c.cc.Invoke(ctx, AnalyserService_Analyse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *analyserServiceClient) AnalyseStream(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[AnalyseRequest, AnalyseResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AnalyserService_ServiceDesc.Streams[0], AnalyserService_AnalyseStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[AnalyseRequest, AnalyseResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyserService_AnalyseStreamClient = grpc.ClientStreamingClient[AnalyseRequest, AnalyseResponse]
+
+// AnalyserServiceServer is the server API for AnalyserService service.
+// All implementations must embed UnimplementedAnalyserServiceServer
+// for forward compatibility.
+//
+// Analyse download performance series with the same statistics as the cli report.
+type AnalyserServiceServer interface {
+	// Analyse a whole series in one request.
+	Analyse(context.Context, *AnalyseRequest) (*AnalyseResponse, error)
+	// Push a series in chunks, name and format are taken from the first message that set them.
+	AnalyseStream(grpc.ClientStreamingServer[AnalyseRequest, AnalyseResponse]) error
+	mustEmbedUnimplementedAnalyserServiceServer()
+}
+
+// UnimplementedAnalyserServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAnalyserServiceServer struct{}
+
+func (UnimplementedAnalyserServiceServer) Analyse(context.Context, *AnalyseRequest) (*AnalyseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Analyse not implemented")
+}
+func (UnimplementedAnalyserServiceServer) AnalyseStream(grpc.ClientStreamingServer[AnalyseRequest, AnalyseResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method AnalyseStream not implemented")
+}
+func (UnimplementedAnalyserServiceServer) mustEmbedUnimplementedAnalyserServiceServer() {}
+func (UnimplementedAnalyserServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeAnalyserServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AnalyserServiceServer will
+// result in compilation errors.
+type UnsafeAnalyserServiceServer interface {
+	mustEmbedUnimplementedAnalyserServiceServer()
+}
+
+func RegisterAnalyserServiceServer(s grpc.ServiceRegistrar, srv AnalyserServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAnalyserServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AnalyserService_ServiceDesc, srv)
+}
+
+func _AnalyserService_Analyse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AnalyserServiceServer).Analyse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AnalyserService_Analyse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AnalyserServiceServer).Analyse(ctx, req.(*AnalyseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AnalyserService_AnalyseStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AnalyserServiceServer).AnalyseStream(&grpc.GenericServerStream[AnalyseRequest, AnalyseResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AnalyserService_AnalyseStreamServer = grpc.ClientStreamingServer[AnalyseRequest, AnalyseResponse]
+
+// AnalyserService_ServiceDesc is the grpc.ServiceDesc for AnalyserService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AnalyserService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "analyser.v1.AnalyserService",
+	HandlerType: (*AnalyserServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Analyse",
+			Handler:    _AnalyserService_Analyse_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "AnalyseStream",
+			Handler:       _AnalyserService_AnalyseStream_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "analyser.proto",
+}
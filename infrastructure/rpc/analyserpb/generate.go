@@ -0,0 +1,4 @@
+// Package analyserpb contains the protobuf API of the analyser gRPC service.
+package analyserpb
+
+//go:generate protoc -I . --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative analyser.proto
@@ -0,0 +1,243 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/awcjack/samknows-backend-code-test/app"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/rpc/analyserpb"
+	"github.com/awcjack/samknows-backend-code-test/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// limits applied to the gRPC server
+type Options struct {
+	// maximum size of one request message in bytes, 0 means the gRPC default (4 MiB)
+	MaxRecvMsgSize int
+	// maximum number of measurements of one series (every message of a stream together), 0 means unlimited
+	MaxMeasurements int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MaxRecvMsgSize:  4 << 20,
+		MaxMeasurements: 1000000,
+	}
+}
+
+// gRPC service that analyse mesurement series with the statistics of application
+type Server struct {
+	analyserpb.UnimplementedAnalyserServiceServer
+	application app.Application
+	options     Options
+}
+
+func NewServer(application app.Application, options Options) *Server {
+	return &Server{
+		application: application,
+		options:     options,
+	}
+}
+
+// function to serve on listener until ctx is cancelled, in-flight RPCs are finished before returning
+func (s *Server) Serve(ctx context.Context, listener net.Listener, options ...grpc.ServerOption) error {
+	if s.options.MaxRecvMsgSize > 0 {
+		options = append([]grpc.ServerOption{grpc.MaxRecvMsgSize(s.options.MaxRecvMsgSize)}, options...)
+	}
+	grpcServer := grpc.NewServer(options...)
+	analyserpb.RegisterAnalyserServiceServer(grpcServer, s)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- grpcServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	grpcServer.GracefulStop()
+	if err := <-serveErr; !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+func (s *Server) Analyse(ctx context.Context, request *analyserpb.AnalyseRequest) (*analyserpb.AnalyseResponse, error) {
+	return s.analyse(request.GetName(), request.GetFormat(), request.GetMeasurements())
+}
+
+func (s *Server) AnalyseStream(stream analyserpb.AnalyserService_AnalyseStreamServer) error {
+	var name string
+	var format analyserpb.Format
+	measurements := make([]*analyserpb.Measurement, 0)
+
+	for {
+		request, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if name == "" {
+			name = request.GetName()
+		}
+		if format == analyserpb.Format_FORMAT_UNSPECIFIED {
+			format = request.GetFormat()
+		}
+		// series is kept in memory until the stream is closed, so it is rejected as soon as it is too long
+		if s.tooLong(len(measurements) + len(request.GetMeasurements())) {
+			return s.tooLongError()
+		}
+		measurements = append(measurements, request.GetMeasurements()...)
+	}
+
+	response, err := s.analyse(name, format, measurements)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(response)
+}
+
+// function to analyse the series and render it in the requested format
+func (s *Server) analyse(name string, format analyserpb.Format, measurements []*analyserpb.Measurement) (*analyserpb.AnalyseResponse, error) {
+	if len(measurements) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "mesurement series is empty")
+	}
+	if s.tooLong(len(measurements)) {
+		return nil, s.tooLongError()
+	}
+
+	content := make([]types.Mesurement, 0, len(measurements))
+	for _, measurement := range measurements {
+		if measurement.GetDtime() == nil {
+			return nil, status.Error(codes.InvalidArgument, "dtime is required")
+		}
+		if err := measurement.GetDtime().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid dtime: %s", err)
+		}
+
+		content = append(content, types.Mesurement{
+			MetricValue: measurement.GetMetricValue(),
+			Dtime: types.JSONTime{
+				Time:      measurement.GetDtime().AsTime(),
+				WallClock: measurement.GetWallClock(),
+			},
+		})
+	}
+
+	report := s.application.Analyse(types.InputFormat{
+		Name:    name,
+		Content: content,
+	})
+	response := &analyserpb.AnalyseResponse{
+		Report: toProtoReport(report),
+	}
+
+	if format != analyserpb.Format_FORMAT_UNSPECIFIED {
+		reportFormat, ok := formats[format]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown format %s", format)
+		}
+
+		rendered, err := s.application.Render(report, reportFormat)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		response.Rendered = rendered
+		response.ContentType = reportFormat.ContentType()
+	}
+
+	return response, nil
+}
+
+// function to check whether series of count measurements exceed MaxMeasurements
+func (s *Server) tooLong(count int) bool {
+	return s.options.MaxMeasurements > 0 && count > s.options.MaxMeasurements
+}
+
+func (s *Server) tooLongError() error {
+	return status.Errorf(codes.ResourceExhausted, "mesurement series is longer than %d measurements", s.options.MaxMeasurements)
+}
+
+var formats = map[analyserpb.Format]app.Format{
+	analyserpb.Format_FORMAT_TEXT:          app.FormatText,
+	analyserpb.Format_FORMAT_JSON:          app.FormatJSON,
+	analyserpb.Format_FORMAT_HTML:          app.FormatHTML,
+	analyserpb.Format_FORMAT_MARKDOWN:      app.FormatMarkdown,
+	analyserpb.Format_FORMAT_LINE_PROTOCOL: app.FormatLineProtocol,
+}
+
+func toProtoStatistics(statistics types.Statistics) *analyserpb.Statistics {
+	return &analyserpb.Statistics{
+		Count:         int64(statistics.Count),
+		Min:           statistics.Min,
+		Max:           statistics.Max,
+		Mean:          statistics.Mean,
+		Median:        statistics.Median,
+		FirstQuartile: statistics.FirstQuartile,
+		Iqr:           statistics.IQR,
+	}
+}
+
+func toProtoReport(report types.Report) *analyserpb.Report {
+	result := &analyserpb.Report{
+		Name:                   report.Name,
+		From:                   timestamppb.New(report.From),
+		To:                     timestamppb.New(report.To),
+		Unit:                   report.Unit,
+		UnitExponent:           int32(report.UnitExponent),
+		Statistics:             toProtoStatistics(report.Statistics),
+		UnderPerformingPeriods: report.UnderPerformingPeriods,
+		UnderPerformingDays:    int64(report.UnderPerformingDays),
+	}
+
+	for _, period := range report.UnderPerformingRanges {
+		result.UnderPerformingRanges = append(result.UnderPerformingRanges, &analyserpb.Period{
+			From: timestamppb.New(period.From),
+			To:   timestamppb.New(period.To),
+			Days: int64(period.Days),
+		})
+	}
+
+	for _, aggregation := range report.Aggregations {
+		buckets := make([]*analyserpb.Bucket, 0, len(aggregation.Buckets))
+		for _, bucket := range aggregation.Buckets {
+			buckets = append(buckets, &analyserpb.Bucket{
+				Label:                bucket.Label,
+				Statistics:           toProtoStatistics(bucket.Statistics),
+				UnderPerformingCount: int64(bucket.UnderPerformingCount),
+			})
+		}
+
+		result.Aggregations = append(result.Aggregations, &analyserpb.Aggregation{
+			Name:    aggregation.Name,
+			Buckets: buckets,
+		})
+	}
+
+	if report.Peak != nil {
+		result.Peak = &analyserpb.PeakAnalysis{
+			Window:          report.Peak.Window,
+			Timezone:        report.Peak.Timezone,
+			Peak:            toProtoStatistics(report.Peak.Peak),
+			OffPeak:         toProtoStatistics(report.Peak.OffPeak),
+			Threshold:       report.Peak.Threshold,
+			Ratio:           report.Peak.Ratio,
+			UnderPerforming: report.Peak.UnderPerforming,
+			RatioAvailable:  report.Peak.RatioAvailable,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,238 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/app"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/rpc/analyserpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// function to start server on in-process listener and return client connected to it
+func newClient(t *testing.T, options Options) analyserpb.AnalyserServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(app.NewApplication(nil, nil), options).Serve(ctx, listener)
+	}()
+
+	conn, err := grpc.DialContext(ctx, "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected nil, but got %v", err)
+		}
+	})
+
+	return analyserpb.NewAnalyserServiceClient(conn)
+}
+
+// daily series with under-performing last day
+func dailySeries() []*analyserpb.Measurement {
+	result := make([]*analyserpb.Measurement, 0)
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		value := 1000000.0
+		if i == 4 {
+			value = 10
+		}
+		result = append(result, &analyserpb.Measurement{
+			MetricValue: value,
+			Dtime:       timestamppb.New(start.AddDate(0, 0, i)),
+			WallClock:   true,
+		})
+	}
+
+	return result
+}
+
+func TestAnalyse(t *testing.T) {
+	client := newClient(t, DefaultOptions())
+
+	response, err := client.Analyse(context.Background(), &analyserpb.AnalyseRequest{
+		Name:         "device",
+		Measurements: dailySeries(),
+		Format:       analyserpb.Format_FORMAT_TEXT,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := response.GetReport()
+	if report.GetName() != "device" || report.GetStatistics().GetCount() != 5 {
+		t.Errorf("Expected get device with 5 mesurement, but got %s with %d", report.GetName(), report.GetStatistics().GetCount())
+	}
+	if len(report.GetUnderPerformingPeriods()) != 1 || report.GetUnderPerformingPeriods()[0] != "2021-01-05" {
+		t.Errorf("Expected get [2021-01-05], but got %v", report.GetUnderPerformingPeriods())
+	}
+	if report.GetUnderPerformingDays() != 1 || len(report.GetUnderPerformingRanges()) != 1 || report.GetUnderPerformingRanges()[0].GetDays() != 1 {
+		t.Errorf("Expected get 1 under-performing day in 1 range, but got %d in %v", report.GetUnderPerformingDays(), report.GetUnderPerformingRanges())
+	}
+	if !strings.Contains(string(response.GetRendered()), "SamKnows Metric Analyser v1.0.0") || response.GetContentType() != app.FormatText.ContentType() {
+		t.Errorf("Expected get text report, but got %s (%s)", response.GetRendered(), response.GetContentType())
+	}
+}
+
+func TestAnalyseStream(t *testing.T) {
+	client := newClient(t, DefaultOptions())
+
+	stream, err := client.AnalyseStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, measurement := range dailySeries() {
+		request := &analyserpb.AnalyseRequest{Measurements: []*analyserpb.Measurement{measurement}}
+		if i == 0 {
+			request.Name = "device"
+		}
+		if err := stream.Send(request); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	response, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := response.GetReport()
+	if report.GetName() != "device" || report.GetStatistics().GetCount() != 5 {
+		t.Errorf("Expected get device with 5 mesurement, but got %s with %d", report.GetName(), report.GetStatistics().GetCount())
+	}
+	if report.GetFrom().AsTime().Format("2006-01-02") != "2021-01-01" || report.GetTo().AsTime().Format("2006-01-02") != "2021-01-05" {
+		t.Errorf("Expected get 2021-01-01 to 2021-01-05, but got %v to %v", report.GetFrom().AsTime(), report.GetTo().AsTime())
+	}
+	if len(response.GetRendered()) != 0 {
+		t.Errorf("Expected no rendered report, but got %s", response.GetRendered())
+	}
+}
+
+func TestAnalyseInvalidArgument(t *testing.T) {
+	client := newClient(t, DefaultOptions())
+
+	type testcase struct {
+		name    string
+		request *analyserpb.AnalyseRequest
+	}
+
+	testcases := []testcase{
+		{
+			name:    "Empty",
+			request: &analyserpb.AnalyseRequest{},
+		},
+		{
+			name: "Missing dtime",
+			request: &analyserpb.AnalyseRequest{
+				Measurements: []*analyserpb.Measurement{{MetricValue: 1}},
+			},
+		},
+		{
+			name: "Unknown format",
+			request: &analyserpb.AnalyseRequest{
+				Measurements: dailySeries(),
+				Format:       analyserpb.Format(42),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := client.Analyse(context.Background(), tc.request)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("Expected get %v, but got %v", codes.InvalidArgument, err)
+			}
+		})
+	}
+}
+
+func TestAnalyseFormat(t *testing.T) {
+	client := newClient(t, DefaultOptions())
+
+	type testcase struct {
+		name     string
+		format   analyserpb.Format
+		expected app.Format
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Markdown",
+			format:   analyserpb.Format_FORMAT_MARKDOWN,
+			expected: app.FormatMarkdown,
+		},
+		{
+			name:     "Line protocol",
+			format:   analyserpb.Format_FORMAT_LINE_PROTOCOL,
+			expected: app.FormatLineProtocol,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := client.Analyse(context.Background(), &analyserpb.AnalyseRequest{
+				Name:         "device",
+				Measurements: dailySeries(),
+				Format:       tc.format,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.GetContentType() != tc.expected.ContentType() || len(response.GetRendered()) == 0 {
+				t.Errorf("Expected get %s report, but got %s (%s)", tc.expected.ContentType(), response.GetRendered(), response.GetContentType())
+			}
+		})
+	}
+}
+
+func TestAnalyseMaxMeasurements(t *testing.T) {
+	client := newClient(t, Options{MaxMeasurements: 3})
+
+	_, err := client.Analyse(context.Background(), &analyserpb.AnalyseRequest{Measurements: dailySeries()})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected get %v, but got %v", codes.ResourceExhausted, err)
+	}
+
+	stream, err := client.AnalyseStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, measurement := range dailySeries() {
+		// server may already have closed the stream, the error is returned by CloseAndRecv
+		if err := stream.Send(&analyserpb.AnalyseRequest{Measurements: []*analyserpb.Measurement{measurement}}); err != nil {
+			break
+		}
+	}
+	_, err = stream.CloseAndRecv()
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected get %v, but got %v", codes.ResourceExhausted, err)
+	}
+}
+
+func TestAnalyseMaxRecvMsgSize(t *testing.T) {
+	client := newClient(t, Options{MaxRecvMsgSize: 64})
+
+	_, err := client.Analyse(context.Background(), &analyserpb.AnalyseRequest{Measurements: dailySeries()})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Expected get %v, but got %v", codes.ResourceExhausted, err)
+	}
+}
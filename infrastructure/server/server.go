@@ -0,0 +1,258 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/app"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/openmetrics"
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/reader"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// limits applied to the HTTP server
+type Options struct {
+	// maximum size of request body in bytes
+	MaxBodyBytes int64
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// maximum time spent on analysing one request
+	HandlerTimeout time.Duration
+	// maximum time to wait for in-flight requests when shutting down
+	ShutdownTimeout time.Duration
+	// maximum number of devices exposed on /metrics, device posted least recently is dropped first (0 means unlimited)
+	MaxDevices int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MaxBodyBytes:    10 << 20,
+		ReadTimeout:     10 * time.Second,
+		WriteTimeout:    30 * time.Second,
+		HandlerTimeout:  25 * time.Second,
+		ShutdownTimeout: 10 * time.Second,
+		MaxDevices:      1000,
+	}
+}
+
+// HTTP API that analyse posted mesurement series with the statistics of application
+type Server struct {
+	application app.Application
+	options     Options
+	// latest report of every device analysed, exposed on /metrics
+	registry *openmetrics.Registry
+}
+
+func NewServer(application app.Application, options Options) Server {
+	return Server{
+		application: application,
+		options:     options,
+		registry:    openmetrics.NewRegistryWithLimit(options.MaxDevices),
+	}
+}
+
+// http handler serving every endpoint
+func (s Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/analyse", s.handleAnalyse)
+	mux.HandleFunc("/healthz", s.handleHealth)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+
+	return http.TimeoutHandler(mux, s.options.HandlerTimeout, `{"error":"request timeout"}`)
+}
+
+// function to listen on addr and serve until ctx is cancelled
+func (s Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// function to serve on listener until ctx is cancelled, in-flight requests are given ShutdownTimeout to finish
+func (s Server) Serve(ctx context.Context, listener net.Listener) error {
+	httpServer := &http.Server{
+		Handler:           s.Handler(),
+		ReadTimeout:       s.options.ReadTimeout,
+		ReadHeaderTimeout: s.options.ReadTimeout,
+		WriteTimeout:      s.options.WriteTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.options.ShutdownTimeout)
+	defer cancel()
+	err := httpServer.Shutdown(shutdownCtx)
+	if err != nil {
+		return err
+	}
+
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+func (s Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":"ok"}`))
+}
+
+// GET /metrics expose statistics of the latest report of every device in OpenMetrics format
+func (s Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Content-Type", openmetrics.ContentType)
+	s.registry.WriteTo(w)
+}
+
+// POST /analyse?name=device with JSON array or CSV body, response format is chosen by Accept header
+func (s Server) handleAnalyse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	w.Header().Set("Vary", "Accept")
+	format, ok := negotiate(r.Header.Get("Accept"))
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, "acceptable media types are application/json, text/plain, text/html and text/markdown")
+		return
+	}
+
+	decode := reader.DecodeJSON
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		switch {
+		case err != nil:
+			writeError(w, http.StatusUnsupportedMediaType, "invalid Content-Type")
+			return
+		case mediaType == "application/json":
+			decode = reader.DecodeJSON
+		case mediaType == "text/csv":
+			decode = reader.DecodeCSV
+		default:
+			writeError(w, http.StatusUnsupportedMediaType, "supported Content-Type are application/json and text/csv")
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.options.MaxBodyBytes))
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body is larger than %d bytes", s.options.MaxBodyBytes))
+			return
+		}
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	mesurements, err := decode(body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid mesurement series: %s", err))
+		return
+	}
+	if len(mesurements) == 0 {
+		writeError(w, http.StatusBadRequest, "mesurement series is empty")
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = "request"
+	}
+
+	report := s.application.Analyse(types.InputFormat{
+		Name:    name,
+		Content: mesurements,
+	})
+	s.registry.Observe(name, report)
+
+	content, err := s.application.Render(report, format)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Write(content)
+}
+
+// function to write error as JSON body
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// function to choose report format from Accept header (JSON when header is missing)
+func negotiate(accept string) (app.Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return app.FormatJSON, true
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+
+	ranges := make([]mediaRange, 0)
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			quality, err = strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			ranges = append(ranges, mediaRange{mediaType: mediaType, quality: quality})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, r := range ranges {
+		for _, format := range app.Formats {
+			mediaType, _, _ := mime.ParseMediaType(format.ContentType())
+			if r.mediaType == mediaType || r.mediaType == "*/*" || r.mediaType == strings.Split(mediaType, "/")[0]+"/*" {
+				return format, true
+			}
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/app"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+const series = `[
+	{"metricValue": 1000000, "dtime": "2021-01-01"},
+	{"metricValue": 1000000, "dtime": "2021-01-02"},
+	{"metricValue": 1000000, "dtime": "2021-01-03"},
+	{"metricValue": 1000000, "dtime": "2021-01-04"},
+	{"metricValue": 10, "dtime": "2021-01-05"}
+]`
+
+func TestHandleAnalyse(t *testing.T) {
+	type testcase struct {
+		name        string
+		method      string
+		contentType string
+		accept      string
+		body        string
+		status      int
+		resultType  string
+		contains    string
+	}
+
+	testcases := []testcase{
+		{
+			name:        "JSON",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        series,
+			status:      http.StatusOK,
+			resultType:  "application/json",
+			contains:    `"underPerformingPeriods": [`,
+		},
+		{
+			name:        "CSV to text",
+			method:      http.MethodPost,
+			contentType: "text/csv",
+			accept:      "text/plain",
+			body:        "dtime,metricValue\n2021-01-01,1000000\n2021-01-02,2000000\n",
+			status:      http.StatusOK,
+			resultType:  "text/plain; charset=utf-8",
+			contains:    "Unit: Megabits per second",
+		},
+		{
+			name:        "HTML preferred by quality",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			accept:      "application/json;q=0.5, text/html",
+			body:        series,
+			status:      http.StatusOK,
+			resultType:  "text/html; charset=utf-8",
+			contains:    "<h1>SamKnows Metric Analyser v1.0.0</h1>",
+		},
+		{
+			name:        "Markdown",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			accept:      "text/markdown",
+			body:        series,
+			status:      http.StatusOK,
+			resultType:  "text/markdown; charset=utf-8",
+			contains:    "- The period 2021-01-05 was under-performing.",
+		},
+		{
+			name:        "Wildcard",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			accept:      "text/*",
+			body:        series,
+			status:      http.StatusOK,
+			resultType:  "text/plain; charset=utf-8",
+			contains:    "was under-performing.",
+		},
+		{
+			name:        "Not acceptable",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			accept:      "image/png",
+			body:        series,
+			status:      http.StatusNotAcceptable,
+		},
+		{
+			name:        "Unsupported media type",
+			method:      http.MethodPost,
+			contentType: "application/xml",
+			body:        series,
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "Invalid body",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        `[{"metricValue": "1", "dtime": "2021-01-01"}]`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "CSV not finite",
+			method:      http.MethodPost,
+			contentType: "text/csv",
+			body:        "dtime,metricValue\n2021-01-01,1000000\n2021-01-02,NaN\n",
+			status:      http.StatusBadRequest,
+			contains:    `line 3: metricValue \"NaN\" is not a finite number`,
+		},
+		{
+			name:        "CSV infinite",
+			method:      http.MethodPost,
+			contentType: "text/csv",
+			body:        "dtime,metricValue\n2021-01-01,-Inf\n",
+			status:      http.StatusBadRequest,
+			contains:    "line 2",
+		},
+		{
+			name:        "Empty series",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        `[]`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "Too large",
+			method:      http.MethodPost,
+			contentType: "application/json",
+			body:        strings.Repeat(" ", 2048) + series,
+			status:      http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "Method not allowed",
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+		},
+	}
+
+	options := DefaultOptions()
+	options.MaxBodyBytes = 1024
+	handler := NewServer(app.NewApplication(nil, nil), options).Handler()
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			request := httptest.NewRequest(tc.method, "/analyse?name=device", strings.NewReader(tc.body))
+			if tc.contentType != "" {
+				request.Header.Set("Content-Type", tc.contentType)
+			}
+			if tc.accept != "" {
+				request.Header.Set("Accept", tc.accept)
+			}
+			recorder := httptest.NewRecorder()
+
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tc.status {
+				t.Fatalf("Expected get %d, but got %d (%s)", tc.status, recorder.Code, recorder.Body.String())
+			}
+			if tc.resultType != "" && recorder.Header().Get("Content-Type") != tc.resultType {
+				t.Errorf("Expected get %s, but got %s", tc.resultType, recorder.Header().Get("Content-Type"))
+			}
+			if !strings.Contains(recorder.Body.String(), tc.contains) {
+				t.Errorf("Expected body containing %q, but got %s", tc.contains, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleAnalyseReport(t *testing.T) {
+	handler := NewServer(app.NewApplication(nil, nil), DefaultOptions()).Handler()
+	request := httptest.NewRequest(http.MethodPost, "/analyse?name=device", strings.NewReader(series))
+	recorder := httptest.NewRecorder()
+
+	handler.ServeHTTP(recorder, request)
+
+	var report types.Report
+	err := json.Unmarshal(recorder.Body.Bytes(), &report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Name != "device" || report.Statistics.Count != 5 {
+		t.Errorf("Expected get device with 5 mesurement, but got %s with %d", report.Name, report.Statistics.Count)
+	}
+	if len(report.UnderPerformingPeriods) != 1 || report.UnderPerformingPeriods[0] != "2021-01-05" {
+		t.Errorf("Expected get [2021-01-05], but got %v", report.UnderPerformingPeriods)
+	}
+}
+
+func TestHandleMetrics(t *testing.T) {
+	handler := NewServer(app.NewApplication(nil, nil), DefaultOptions()).Handler()
+
+	for _, name := range []string{"device1", "device2"} {
+		request := httptest.NewRequest(http.MethodPost, "/analyse?name="+name, strings.NewReader(series))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected get %d, but got %d", http.StatusOK, recorder.Code)
+	}
+	if !strings.HasPrefix(recorder.Header().Get("Content-Type"), "application/openmetrics-text") {
+		t.Errorf("Expected get application/openmetrics-text, but got %s", recorder.Header().Get("Content-Type"))
+	}
+	for _, sample := range []string{
+		`performance_analyser_under_performing_days{device="device1"} 1`,
+		`performance_analyser_measurements{device="device2"} 5`,
+	} {
+		if !strings.Contains(recorder.Body.String(), sample) {
+			t.Errorf("Expected body containing %s, but got %s", sample, recorder.Body.String())
+		}
+	}
+}
+
+func TestHandleMetricsMaxDevices(t *testing.T) {
+	options := DefaultOptions()
+	options.MaxDevices = 2
+	handler := NewServer(app.NewApplication(nil, nil), options).Handler()
+
+	for _, name := range []string{"device1", "device2", "device3"} {
+		request := httptest.NewRequest(http.MethodPost, "/analyse?name="+name, strings.NewReader(series))
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	// device posted least recently is dropped
+	if strings.Contains(recorder.Body.String(), `device="device1"`) {
+		t.Errorf("Expected body without device1, but got %s", recorder.Body.String())
+	}
+	for _, device := range []string{"device2", "device3"} {
+		if !strings.Contains(recorder.Body.String(), `performance_analyser_measurements{device="`+device+`"} 5`) {
+			t.Errorf("Expected body containing %s, but got %s", device, recorder.Body.String())
+		}
+	}
+}
+
+func TestServeGracefulShutdown(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(app.NewApplication(nil, nil), DefaultOptions()).Serve(ctx, listener)
+	}()
+
+	response, err := http.Get("http://" + listener.Addr().String() + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Errorf("Expected get %d, but got %d", http.StatusOK, response.StatusCode)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected nil, but got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
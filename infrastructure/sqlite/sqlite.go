@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+
+	// pure Go driver registered as "sqlite", so no cgo is needed
+	_ "modernc.org/sqlite"
+)
+
+// schema changes applied in order, index + 1 is the schema version stored in PRAGMA user_version
+// every time is stored as integer Unix nanoseconds (UTC), so the same instant written with different UTC offsets is one value and order of it is time order
+var migrations = []string{
+	// 1: mesurement series keyed by device, reports and structured results of analysis
+	// wall clock mesurement keep its wall clock time as if it were UTC, wall_clock column tell them apart
+	`CREATE TABLE measurements (
+		device TEXT NOT NULL,
+		dtime INTEGER NOT NULL,
+		wall_clock INTEGER NOT NULL DEFAULT 1,
+		metric_value REAL NOT NULL,
+		PRIMARY KEY (device, dtime)
+	);
+	CREATE TABLE outputs (
+		name TEXT PRIMARY KEY,
+		content BLOB NOT NULL,
+		written_at INTEGER NOT NULL
+	);
+	CREATE TABLE results (
+		name TEXT PRIMARY KEY,
+		period_from INTEGER NOT NULL,
+		period_to INTEGER NOT NULL,
+		unit TEXT NOT NULL,
+		unit_exponent INTEGER NOT NULL,
+		count INTEGER NOT NULL,
+		min REAL NOT NULL,
+		max REAL NOT NULL,
+		mean REAL NOT NULL,
+		median REAL NOT NULL,
+		first_quartile REAL NOT NULL,
+		iqr REAL NOT NULL,
+		written_at INTEGER NOT NULL
+	);
+	CREATE TABLE under_performing_periods (
+		name TEXT NOT NULL REFERENCES results (name) ON DELETE CASCADE,
+		period_from INTEGER NOT NULL,
+		period_to INTEGER NOT NULL,
+		days INTEGER NOT NULL,
+		PRIMARY KEY (name, period_from)
+	);`,
+}
+
+// function to open database file (created when missing) and migrate it to the latest schema
+func Open(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+
+	err = Migrate(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// function to apply migrations that are newer than the schema version of database
+func Migrate(db *sql.DB) error {
+	var version int
+	err := db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(migrations[i])
+		if err == nil {
+			// PRAGMA does not accept bind parameter
+			_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", i+1))
+		}
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
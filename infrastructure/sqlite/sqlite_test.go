@@ -0,0 +1,73 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analyser.db")
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("INSERT INTO measurements (device, dtime, metric_value) VALUES ('1', ?, 1)", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// reopening migrated database keep the data
+	db, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var version, count int
+	db.QueryRow("PRAGMA user_version").Scan(&version)
+	db.QueryRow("SELECT COUNT(*) FROM measurements").Scan(&count)
+	if version != len(migrations) || count != 1 {
+		t.Errorf("Expected get version %d with 1 mesurement, but got version %d with %d", len(migrations), version, count)
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "analyser.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec("PRAGMA user_version = 99")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Migrate(db)
+	if err == nil || !strings.Contains(err.Error(), "newer") {
+		t.Errorf("Expected get newer version error, but got %v", err)
+	}
+}
+
+func TestMeasurementsSameInstant(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "analyser.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// the same instant in two UTC offsets is the same key
+	instant := time.Date(2021, 1, 1, 18, 30, 0, 0, time.UTC)
+	_, err = db.Exec("INSERT INTO measurements (device, dtime, wall_clock, metric_value) VALUES ('1', ?, 0, 1)", instant.UnixNano())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Exec("INSERT INTO measurements (device, dtime, wall_clock, metric_value) VALUES ('1', ?, 0, 2)", instant.In(time.FixedZone("", 60*60)).UnixNano())
+	if err == nil || !strings.Contains(err.Error(), "UNIQUE") {
+		t.Errorf("Expected get unique constraint error, but got %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+package watcher
+
+import (
+	"context"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/cache"
+	"github.com/fsnotify/fsnotify"
+)
+
+// function called with name (path relative to the directory) and content of input file that is new or modified, content is the same that is compared with the record
+type ProcessFunc func(name string, content []byte) error
+
+type Options struct {
+	// quiet period after last write event before file is considered fully written
+	Debounce time.Duration
+	// watch subdirectories as well (including directory created while watching)
+	Recursive bool
+	// function to check whether file is input (e.g. extension and include/exclude patterns of the reader), nil means every file that is not hidden or temporary
+	Selected func(name string) bool
+	// file larger than this many bytes is skipped without reading it, 0 means unlimited
+	MaxFileSize int64
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Debounce: 500 * time.Millisecond,
+	}
+}
+
+// watcher that process new or modified files in directory
+type Watcher struct {
+	dir     string
+	process ProcessFunc
+	record  cache.Cache
+	options Options
+}
+
+func NewWatcher(dir string, process ProcessFunc, record cache.Cache, options Options) *Watcher {
+	return &Watcher{
+		dir:     dir,
+		process: process,
+		record:  record,
+		options: options,
+	}
+}
+
+// function to process files already in directory, then process files as they are written until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer notifier.Close()
+
+	// start watching before scanning directory, so file written in between is not missed
+	names, err := w.watchTree(notifier, ".")
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		w.handle(name)
+	}
+
+	// file is processed once no write event is received within debounce period
+	timers := make(map[string]*time.Timer)
+	ready := make(chan string)
+	defer func() {
+		for _, timer := range timers {
+			timer.Stop()
+		}
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-notifier.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("watch %s: %s", w.dir, err)
+		case event, ok := <-notifier.Events:
+			if !ok {
+				return nil
+			}
+
+			name, err := filepath.Rel(w.dir, event.Name)
+			if err != nil {
+				continue
+			}
+			name = filepath.ToSlash(name)
+			if ignored(path.Base(name)) {
+				continue
+			}
+
+			if event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename) {
+				if timer, ok := timers[name]; ok {
+					timer.Stop()
+					delete(timers, name)
+				}
+				continue
+			}
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+
+			if event.Has(fsnotify.Create) && w.options.Recursive {
+				info, err := os.Stat(event.Name)
+				if err == nil && info.IsDir() {
+					// files written before the new directory is watched are found by scanning it
+					names, err := w.watchTree(notifier, name)
+					if err != nil {
+						log.Printf("watch %s: %s", name, err)
+					}
+					for _, name := range names {
+						w.handle(name)
+					}
+					continue
+				}
+			}
+
+			if timer, ok := timers[name]; ok {
+				timer.Reset(w.options.Debounce)
+				continue
+			}
+			timers[name] = time.AfterFunc(w.options.Debounce, func() {
+				select {
+				case ready <- name:
+				case <-ctx.Done():
+				}
+			})
+		case name := <-ready:
+			delete(timers, name)
+			w.handle(name)
+		}
+	}
+}
+
+// function to watch directory (and its subdirectories when recursive) and list files already in it
+func (w *Watcher) watchTree(notifier *fsnotify.Watcher, dir string) ([]string, error) {
+	err := notifier.Add(filepath.Join(w.dir, filepath.FromSlash(dir)))
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(w.dir, filepath.FromSlash(dir)))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0)
+	for _, entry := range entries {
+		name := path.Join(dir, entry.Name())
+		if ignored(entry.Name()) {
+			continue
+		}
+
+		if !entry.IsDir() {
+			result = append(result, name)
+			continue
+		}
+		if w.options.Recursive {
+			names, err := w.watchTree(notifier, name)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, names...)
+		}
+	}
+
+	return result, nil
+}
+
+// function to process file when its content is not processed before, error is logged so one bad file does not stop watching
+func (w *Watcher) handle(name string) {
+	if w.options.Selected != nil && !w.options.Selected(name) {
+		return
+	}
+
+	file := filepath.Join(w.dir, filepath.FromSlash(name))
+	info, err := os.Stat(file)
+	if err != nil || info.IsDir() {
+		return
+	}
+	if w.options.MaxFileSize > 0 && info.Size() > w.options.MaxFileSize {
+		log.Printf("skip %s: larger than maximum input size of %d bytes", name, w.options.MaxFileSize)
+		return
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		log.Printf("read %s: %s", name, err)
+		return
+	}
+
+	hash := cache.Key(content)
+	if w.record.Hit(name, hash) {
+		return
+	}
+
+	err = w.process(name, content)
+	if err != nil {
+		// not marked as processed, so it is retried on next write
+		log.Printf("process %s: %s", name, err)
+		return
+	}
+
+	err = w.record.Store(name, hash)
+	if err != nil {
+		log.Printf("record %s: %s", name, err)
+		return
+	}
+	log.Printf("processed %s", name)
+}
+
+// function to check whether file should be skipped (hidden and temporary file written by editor or collector)
+func ignored(name string) bool {
+	return strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") || strings.HasSuffix(name, ".tmp")
+}
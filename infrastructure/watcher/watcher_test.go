@@ -0,0 +1,173 @@
+package watcher
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/cache"
+)
+
+// function to start watcher on dir and return channel receiving processed file names
+func startWatcher(t *testing.T, dir string, statePath string, options Options) chan string {
+	record, err := cache.NewFileCache(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processed := make(chan string, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewWatcher(dir, func(name string, content []byte) error {
+			processed <- name
+			return nil
+		}, record, options).Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Expected nil, but got %v", err)
+		}
+	})
+
+	return processed
+}
+
+func expectProcessed(t *testing.T, processed chan string, expected string) {
+	t.Helper()
+	select {
+	case name := <-processed:
+		if name != expected {
+			t.Errorf("Expected get %s, but got %s", expected, name)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected get %s, but got nothing", expected)
+	}
+}
+
+func expectNothing(t *testing.T, processed chan string) {
+	t.Helper()
+	select {
+	case name := <-processed:
+		t.Errorf("Expected get nothing, but got %s", name)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	err := os.WriteFile(filepath.Join(dir, "existing.json"), []byte("[]"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	processed := startWatcher(t, dir, statePath, Options{Debounce: 50 * time.Millisecond})
+	expectProcessed(t, processed, "existing.json")
+
+	// file written in several chunks is processed once after it is fully written
+	file, err := os.Create(filepath.Join(dir, "new.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range []string{"[", `{"metricValue": 1, "dtime": "2021-01-01"}`, "]"} {
+		file.WriteString(chunk)
+		time.Sleep(10 * time.Millisecond)
+	}
+	file.Close()
+	expectProcessed(t, processed, "new.json")
+	expectNothing(t, processed)
+
+	// rewriting same content is skipped, changed content is processed again
+	os.WriteFile(filepath.Join(dir, "existing.json"), []byte("[]"), 0644)
+	expectNothing(t, processed)
+	os.WriteFile(filepath.Join(dir, "existing.json"), []byte("[ ]"), 0644)
+	expectProcessed(t, processed, "existing.json")
+
+	// hidden and temporary file is ignored
+	os.WriteFile(filepath.Join(dir, ".partial.json"), []byte("["), 0644)
+	os.WriteFile(filepath.Join(dir, "upload.tmp"), []byte("["), 0644)
+	expectNothing(t, processed)
+}
+
+func TestWatcherRestart(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	err := os.WriteFile(filepath.Join(dir, "1.json"), []byte("[]"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := cache.NewFileCache(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = record.Store("1.json", cache.Key([]byte("[]")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// file processed before restart is not processed again
+	processed := startWatcher(t, dir, statePath, Options{Debounce: 50 * time.Millisecond})
+	expectNothing(t, processed)
+}
+
+func TestWatcherSelection(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	err := os.MkdirAll(filepath.Join(dir, "site"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "site", "existing.json"), []byte("[]"), 0644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello"), 0644)
+
+	options := Options{
+		Debounce:  50 * time.Millisecond,
+		Recursive: true,
+		Selected: func(name string) bool {
+			return strings.HasSuffix(name, ".json")
+		},
+	}
+	processed := startWatcher(t, dir, statePath, options)
+	expectProcessed(t, processed, "site/existing.json")
+	expectNothing(t, processed)
+
+	// file not selected is never processed
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("hello again"), 0644)
+	expectNothing(t, processed)
+
+	// file in directory created while watching is processed
+	err = os.MkdirAll(filepath.Join(dir, "other"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	os.WriteFile(filepath.Join(dir, "other", "new.json"), []byte("[]"), 0644)
+	expectProcessed(t, processed, "other/new.json")
+}
+
+func TestWatcherMaxFileSize(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(t.TempDir(), "state.json")
+
+	options := Options{
+		Debounce:    50 * time.Millisecond,
+		MaxFileSize: 10,
+	}
+	processed := startWatcher(t, dir, statePath, options)
+
+	// file over the limit is skipped, file within it is processed
+	os.WriteFile(filepath.Join(dir, "large.json"), []byte(strings.Repeat(" ", 11)), 0644)
+	expectNothing(t, processed)
+	os.WriteFile(filepath.Join(dir, "small.json"), []byte("[]"), 0644)
+	expectProcessed(t, processed, "small.json")
+}
@@ -9,3 +9,13 @@ type Writer interface {
 	WriteMultipleOutput([]types.OutputFormat) error
 	WriteOutput(name string, content []byte) error
 }
+
+// interface that writer can additionally provide to store structured result of analysis (e.g. in database table)
+type ReportWriter interface {
+	WriteReport(name string, report types.Report) error
+}
+
+// interface that writer can additionally provide to tell whether output is already written (e.g. for overwrite policy)
+type ExistenceChecker interface {
+	Exists(name string) (bool, error)
+}
@@ -1,39 +1,243 @@
 package writer
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 
 	"github.com/awcjack/samknows-backend-code-test/types"
 )
 
 var (
-	basePath = "output"
+	DefaultBasePath = "output"
 )
 
-type ioWriter struct{}
+// replaced in test to make rename fail
+var rename = os.Rename
 
-func NewIOWriter() ioWriter {
-	return ioWriter{}
+// name of staging directory created under basePath by WriteMultipleOutput
+const stagingPattern = ".staging-*"
+
+type IOOptions struct {
+	// flush every written file (and its directory) to disk before it is renamed into place, slower but survive power loss
+	Sync bool
+}
+
+type ioWriter struct {
+	basePath string
+	options  IOOptions
+}
+
+// function to make writer that write output files under basePath
+func NewIOWriter(basePath string) ioWriter {
+	return NewIOWriterWithOptions(basePath, IOOptions{})
+}
+
+// function to make writer that write output files under basePath with options
+// staging directories left under basePath by crashed process are removed, it is best effort since basePath may not exist yet
+func NewIOWriterWithOptions(basePath string, options IOOptions) ioWriter {
+	removeStaleStaging(basePath)
+
+	return ioWriter{
+		basePath: basePath,
+		options:  options,
+	}
+}
+
+// function to remove staging directories of WriteMultipleOutput that was never finished
+// directory holding previous outputs (restoring them failed, or process crashed while swapping) is kept since it has their only copy
+func removeStaleStaging(basePath string) {
+	dirs, err := filepath.Glob(filepath.Join(basePath, stagingPattern))
+	if err != nil {
+		return
+	}
+
+	for _, dir := range dirs {
+		backup := false
+		filepath.WalkDir(filepath.Join(dir, "old"), func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() {
+				backup = true
+				return filepath.SkipAll
+			}
+			return nil
+		})
+		if !backup {
+			os.RemoveAll(dir)
+		}
+	}
 }
 
-// write multiple file to filesystem
+// write multiple file to filesystem, either every file is replaced or none of them
+// files are written to staging directory first, then renamed into place one by one and the replaced files are restored if any rename fail
 func (w ioWriter) WriteMultipleOutput(outputs []types.OutputFormat) error {
+	err := os.MkdirAll(w.basePath, 0755)
+	if err != nil {
+		return err
+	}
+	// staging directory is inside basePath so rename never cross filesystem
+	staging, err := os.MkdirTemp(w.basePath, stagingPattern)
+	if err != nil {
+		return err
+	}
+	// kept when previous outputs cannot be restored, since their only copy is inside
+	keep := false
+	defer func() {
+		if !keep {
+			os.RemoveAll(staging)
+		}
+	}()
+
+	// same name written twice keep the last content like writing one by one
 	for _, output := range outputs {
-		err := w.WriteOutput(output.Name, output.Content)
+		err := writeFile(filepath.Join(staging, "new", output.Name), output.Content, w.options.Sync)
 		if err != nil {
 			return err
 		}
 	}
 
+	type swapped struct {
+		target string
+		backup string
+	}
+	done := make([]swapped, 0, len(outputs))
+	rollback := func(cause error) error {
+		restored := true
+		for i := len(done) - 1; i >= 0; i-- {
+			if done[i].backup == "" {
+				os.Remove(done[i].target)
+				continue
+			}
+			err := rename(done[i].backup, done[i].target)
+			if err != nil && !errors.Is(err, fs.ErrNotExist) {
+				restored = false
+			}
+		}
+
+		if !restored {
+			keep = true
+			return fmt.Errorf("%w (restoring previous outputs failed, they are kept in %s)", cause, filepath.Join(staging, "old"))
+		}
+		return cause
+	}
+
+	moved := make(map[string]bool)
+	for _, output := range outputs {
+		if moved[output.Name] {
+			continue
+		}
+		moved[output.Name] = true
+
+		target := filepath.Join(w.basePath, output.Name)
+		err := os.MkdirAll(filepath.Dir(target), 0755)
+		if err != nil {
+			return rollback(err)
+		}
+
+		current := swapped{target: target}
+		_, err = os.Lstat(target)
+		if err == nil {
+			current.backup = filepath.Join(staging, "old", output.Name)
+			err = os.MkdirAll(filepath.Dir(current.backup), 0755)
+			if err == nil {
+				err = rename(target, current.backup)
+			}
+		} else if errors.Is(err, fs.ErrNotExist) {
+			err = nil
+		}
+		if err != nil {
+			return rollback(err)
+		}
+
+		// recorded before new output is moved, so failed move restore the backup as well
+		done = append(done, current)
+		err = rename(filepath.Join(staging, "new", output.Name), target)
+		if err != nil {
+			return rollback(err)
+		}
+	}
+
+	if w.options.Sync {
+		for name := range moved {
+			err := syncDir(filepath.Dir(filepath.Join(w.basePath, name)))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
-// write one file to filesystem
+// write one file to filesystem, directory of nested name (e.g. "sub/device.output") is created
+// content is written to temporary file renamed over the output, so reader never see truncated report
 func (w ioWriter) WriteOutput(name string, content []byte) error {
-	err := os.WriteFile(basePath+"/"+name, content, 0644)
+	return writeFile(filepath.Join(w.basePath, name), content, w.options.Sync)
+}
+
+// check whether file is already written
+func (w ioWriter) Exists(name string) (bool, error) {
+	_, err := os.Stat(filepath.Join(w.basePath, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// function to write file atomically with temporary file in the same directory (hidden and ending with .tmp, so watcher ignore it) renamed over path
+func writeFile(path string, content []byte, sync bool) error {
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, 0755)
 	if err != nil {
 		return err
 	}
 
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// no-op once renamed
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if err == nil {
+		err = tmp.Chmod(0644)
+	}
+	if err == nil && sync {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp.Name(), path)
+	if err != nil {
+		return err
+	}
+
+	if sync {
+		return syncDir(dir)
+	}
 	return nil
 }
+
+// function to flush directory entry of renamed file to disk
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
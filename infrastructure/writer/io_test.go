@@ -0,0 +1,231 @@
+package writer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// function to list every file under dir with content
+func readTree(t *testing.T, dir string) map[string]string {
+	result := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name, _ := filepath.Rel(dir, path)
+		result[filepath.ToSlash(name)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
+
+func TestIOWriterWriteOutput(t *testing.T) {
+	for _, sync := range []bool{false, true} {
+		dir := t.TempDir()
+		w := NewIOWriterWithOptions(dir, IOOptions{Sync: sync})
+
+		for _, content := range []string{"first", "replaced"} {
+			err := w.WriteOutput("site/1.output", []byte(content))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// temporary file is renamed, nothing else is left
+		result := readTree(t, dir)
+		if len(result) != 1 || result["site/1.output"] != "replaced" {
+			t.Errorf("Expected get map[site/1.output:replaced], but got %v", result)
+		}
+		info, err := os.Stat(filepath.Join(dir, "site/1.output"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0644 {
+			t.Errorf("Expected get %v, but got %v", os.FileMode(0644), info.Mode().Perm())
+		}
+	}
+}
+
+func TestIOWriterWriteMultipleOutput(t *testing.T) {
+	type testcase struct {
+		name     string
+		existing map[string]string
+		outputs  []types.OutputFormat
+		expected map[string]string
+		err      bool
+	}
+
+	testcases := []testcase{
+		{
+			name:     "Replace and create",
+			existing: map[string]string{"1.output": "old", "3.output": "kept"},
+			outputs: []types.OutputFormat{
+				{Name: "1.output", Content: []byte("new")},
+				{Name: "site/2.output", Content: []byte("created")},
+			},
+			expected: map[string]string{"1.output": "new", "site/2.output": "created", "3.output": "kept"},
+		},
+		{
+			name:     "Same name twice",
+			existing: map[string]string{},
+			outputs: []types.OutputFormat{
+				{Name: "1.output", Content: []byte("first")},
+				{Name: "1.output", Content: []byte("second")},
+			},
+			expected: map[string]string{"1.output": "second"},
+		},
+		{
+			name:     "Rollback",
+			existing: map[string]string{"1.output": "old", "blocked": "file"},
+			outputs: []types.OutputFormat{
+				{Name: "1.output", Content: []byte("new")},
+				{Name: "2.output", Content: []byte("created")},
+				// directory cannot be made where file exist
+				{Name: "blocked/3.output", Content: []byte("never")},
+			},
+			expected: map[string]string{"1.output": "old", "blocked": "file"},
+			err:      true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, content := range tc.existing {
+				err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := NewIOWriter(dir).WriteMultipleOutput(tc.outputs)
+			if tc.err != (err != nil) {
+				t.Errorf("Expected get error %v, but got %v", tc.err, err)
+			}
+
+			// staging directory is removed as well
+			result := readTree(t, dir)
+			if len(result) != len(tc.expected) {
+				t.Errorf("Expected get %v, but got %v", tc.expected, result)
+			}
+			for name, content := range tc.expected {
+				if result[name] != content {
+					t.Errorf("Expected get %v, but got %v", tc.expected, result)
+				}
+			}
+		})
+	}
+}
+
+func TestIOWriterWriteMultipleOutputRestoreFailed(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "1.output"), []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(filepath.Join(dir, "blocked"), []byte("file"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// moving backup back fails
+	rename = func(from string, to string) error {
+		if strings.Contains(filepath.ToSlash(from), "/old/") {
+			return errors.New("rename failed")
+		}
+		return os.Rename(from, to)
+	}
+	t.Cleanup(func() {
+		rename = os.Rename
+	})
+
+	err = NewIOWriter(dir).WriteMultipleOutput([]types.OutputFormat{
+		{Name: "1.output", Content: []byte("new")},
+		{Name: "blocked/2.output", Content: []byte("never")},
+	})
+	if err == nil || !strings.Contains(err.Error(), "restoring previous outputs failed") {
+		t.Fatalf("Expected get restore error, but got %v", err)
+	}
+
+	// previous output is kept in staging directory named by the error
+	backups, err := filepath.Glob(filepath.Join(dir, ".staging-*", "old", "1.output"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 {
+		t.Fatalf("Expected get 1 backup, but got %v", backups)
+	}
+	content, err := os.ReadFile(backups[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "old" {
+		t.Errorf("Expected get old, but got %s", content)
+	}
+}
+
+func TestIOWriterExists(t *testing.T) {
+	dir := t.TempDir()
+	err := NewIOWriter(dir).WriteOutput("site/1.output", []byte("report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expected := range map[string]bool{"site/1.output": true, "site/2.output": false, "1.output": false} {
+		exists, err := NewIOWriter(dir).Exists(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != expected {
+			t.Errorf("Expected get %v for %s, but got %v", expected, name, exists)
+		}
+	}
+}
+
+func TestNewIOWriterRemoveStaleStaging(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		// crashed before swapping, only new outputs inside
+		".staging-1/new/1.output",
+		// crashed while swapping, previous output has its only copy inside
+		".staging-2/old/site/2.output",
+		"1.output",
+	}
+	for _, name := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		err := os.MkdirAll(filepath.Dir(path), 0755)
+		if err == nil {
+			err = os.WriteFile(path, []byte("report"), 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// staging created just before crash, nothing written yet
+	err := os.Mkdir(filepath.Join(dir, ".staging-3"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	NewIOWriter(dir)
+
+	for name, expected := range map[string]bool{".staging-1": false, ".staging-2/old/site/2.output": true, ".staging-3": false, "1.output": true} {
+		_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(name)))
+		if exists := err == nil; exists != expected {
+			t.Errorf("Expected get %v for %s, but got %v", expected, name, exists)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package writer
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// writer that keep outputs and reports in memory (e.g. to assert every report in test)
+type memoryWriter struct {
+	mu      sync.Mutex
+	outputs map[string][]byte
+	reports map[string]types.Report
+}
+
+func NewMemoryWriter() *memoryWriter {
+	return &memoryWriter{
+		outputs: make(map[string][]byte),
+		reports: make(map[string]types.Report),
+	}
+}
+
+// keep multiple outputs, replacing output with same name
+func (w *memoryWriter) WriteMultipleOutput(outputs []types.OutputFormat) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, output := range outputs {
+		w.outputs[output.Name] = append([]byte(nil), output.Content...)
+	}
+
+	return nil
+}
+
+// keep one output, replacing output with same name
+func (w *memoryWriter) WriteOutput(name string, content []byte) error {
+	return w.WriteMultipleOutput([]types.OutputFormat{{Name: name, Content: content}})
+}
+
+// keep structured report, replacing report with same name
+func (w *memoryWriter) WriteReport(name string, report types.Report) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.reports[name] = report
+	return nil
+}
+
+// check whether output is already kept
+func (w *memoryWriter) Exists(name string) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.outputs[name]
+	return ok, nil
+}
+
+// function to get name of every kept output in lexical order
+func (w *memoryWriter) Names() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	result := make([]string, 0, len(w.outputs))
+	for name := range w.outputs {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// function to get content of kept output
+func (w *memoryWriter) Output(name string) ([]byte, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	content, ok := w.outputs[name]
+	return content, ok
+}
+
+// function to get kept report
+func (w *memoryWriter) Report(name string) (types.Report, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	report, ok := w.reports[name]
+	return report, ok
+}
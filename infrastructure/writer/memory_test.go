@@ -0,0 +1,48 @@
+package writer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestMemoryWriter(t *testing.T) {
+	w := NewMemoryWriter()
+
+	content := []byte("first")
+	err := w.WriteMultipleOutput([]types.OutputFormat{
+		{Name: "site/2.output", Content: content},
+		{Name: "1.output", Content: []byte("first")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// kept output is not changed by caller reusing its buffer
+	copy(content, "xxxxx")
+	err = w.WriteOutput("1.output", []byte("replaced"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := w.Names(); !reflect.DeepEqual(names, []string{"1.output", "site/2.output"}) {
+		t.Errorf("Expected get %v, but got %v", []string{"1.output", "site/2.output"}, names)
+	}
+	for name, expected := range map[string]string{"1.output": "replaced", "site/2.output": "first"} {
+		result, _ := w.Output(name)
+		if string(result) != expected {
+			t.Errorf("Expected get %v, but got %v", expected, string(result))
+		}
+	}
+	if exists, _ := w.Exists("3.output"); exists {
+		t.Errorf("Expected get %v, but got %v", false, exists)
+	}
+
+	err = w.WriteReport("1", types.Report{Name: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report, ok := w.Report("1"); !ok || report.Name != "1" {
+		t.Errorf("Expected get report 1, but got %v", report)
+	}
+}
@@ -0,0 +1,60 @@
+package writer
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/openmetrics"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+// writer that pass outputs to next writer and keep OpenMetrics file of per-device statistics up to date (e.g. for node_exporter textfile collector)
+type openMetricsWriter struct {
+	Writer
+	path     string
+	registry *openmetrics.Registry
+	mu       sync.Mutex
+}
+
+func NewOpenMetricsWriter(next Writer, path string) *openMetricsWriter {
+	return &openMetricsWriter{
+		Writer:   next,
+		path:     path,
+		registry: openmetrics.NewRegistry(),
+	}
+}
+
+// check whether output is already written by next writer
+func (w *openMetricsWriter) Exists(name string) (bool, error) {
+	checker, ok := w.Writer.(ExistenceChecker)
+	if !ok {
+		return false, errors.New("writer cannot check whether output exists")
+	}
+
+	return checker.Exists(name)
+}
+
+// record statistics of device and rewrite metrics file
+func (w *openMetricsWriter) WriteReport(name string, report types.Report) error {
+	if reportWriter, ok := w.Writer.(ReportWriter); ok {
+		err := reportWriter.WriteReport(name, report)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.registry.Observe(name, report)
+
+	var buf bytes.Buffer
+	_, err := w.registry.WriteTo(&buf)
+	if err != nil {
+		return err
+	}
+
+	// collector must never read half written file
+	return writeFile(w.path, buf.Bytes(), false)
+}
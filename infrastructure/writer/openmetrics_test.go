@@ -0,0 +1,50 @@
+package writer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestOpenMetricsWriter(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "analyser.prom")
+	w := NewOpenMetricsWriter(NewIOWriter(dir), path)
+
+	// rendered output is passed to next writer
+	err := w.WriteOutput("1.output", []byte("report"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "1.output"))
+	if err != nil || string(content) != "report" {
+		t.Errorf("Expected get report, but got %s (%v)", content, err)
+	}
+
+	for _, name := range []string{"1", "2"} {
+		err := w.WriteReport(name, types.Report{Statistics: types.Statistics{Mean: 10}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, device := range []string{"1", "2"} {
+		sample := `performance_analyser_mean_bytes_per_second{device="` + device + `"} 10`
+		if !strings.Contains(string(content), sample) {
+			t.Errorf("Expected get content containing %s, but got %s", sample, content)
+		}
+	}
+
+	// temporary file is not left behind
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("Expected get 2 files, but got %d", len(entries))
+	}
+}
@@ -0,0 +1,105 @@
+package writer
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+type sqliteWriter struct {
+	db *sql.DB
+}
+
+// function to make writer that store rendered reports in outputs table and structured results in results table (see sqlite.Open), times are Unix nanoseconds
+func NewSQLiteWriter(db *sql.DB) sqliteWriter {
+	return sqliteWriter{
+		db: db,
+	}
+}
+
+// write multiple reports in one transaction
+func (w sqliteWriter) WriteMultipleOutput(outputs []types.OutputFormat) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	for _, output := range outputs {
+		err := writeOutput(tx, output.Name, output.Content)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// write one report, replacing report with same name
+func (w sqliteWriter) WriteOutput(name string, content []byte) error {
+	return writeOutput(w.db, name, content)
+}
+
+// check whether report with name is already stored
+func (w sqliteWriter) Exists(name string) (bool, error) {
+	var count int
+	err := w.db.QueryRow("SELECT COUNT(*) FROM outputs WHERE name = ?", name).Scan(&count)
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// write statistics and under-performing periods of report, replacing result with same name
+func (w sqliteWriter) WriteReport(name string, report types.Report) error {
+	tx, err := w.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT OR REPLACE INTO results
+		(name, period_from, period_to, unit, unit_exponent, count, min, max, mean, median, first_quartile, iqr, written_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		name,
+		report.From.UnixNano(),
+		report.To.UnixNano(),
+		report.Unit,
+		report.UnitExponent,
+		report.Statistics.Count,
+		report.Statistics.Min,
+		report.Statistics.Max,
+		report.Statistics.Mean,
+		report.Statistics.Median,
+		report.Statistics.FirstQuartile,
+		report.Statistics.IQR,
+		time.Now().UnixNano(),
+	)
+	if err == nil {
+		// remove periods of previous result with same name
+		_, err = tx.Exec("DELETE FROM under_performing_periods WHERE name = ?", name)
+	}
+	for _, period := range report.UnderPerformingRanges {
+		if err != nil {
+			break
+		}
+		_, err = tx.Exec("INSERT INTO under_performing_periods (name, period_from, period_to, days) VALUES (?, ?, ?, ?)", name, period.From.UnixNano(), period.To.UnixNano(), period.Days)
+	}
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// query executer shared by database and transaction
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func writeOutput(db execer, name string, content []byte) error {
+	_, err := db.Exec("INSERT OR REPLACE INTO outputs (name, content, written_at) VALUES (?, ?, ?)", name, content, time.Now().UnixNano())
+	return err
+}
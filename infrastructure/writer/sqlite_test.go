@@ -0,0 +1,93 @@
+package writer
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/awcjack/samknows-backend-code-test/infrastructure/sqlite"
+	"github.com/awcjack/samknows-backend-code-test/types"
+)
+
+func TestSQLiteWriter(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "analyser.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	w := NewSQLiteWriter(db)
+
+	err = w.WriteMultipleOutput([]types.OutputFormat{
+		{Name: "1.output", Content: []byte("first")},
+		{Name: "2.output", Content: []byte("second")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = w.WriteOutput("1.output", []byte("replaced"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var content string
+	var count int
+	db.QueryRow("SELECT content FROM outputs WHERE name = '1.output'").Scan(&content)
+	db.QueryRow("SELECT COUNT(*) FROM outputs").Scan(&count)
+	if content != "replaced" || count != 2 {
+		t.Errorf("Expected get replaced with 2 output, but got %s with %d", content, count)
+	}
+
+	for name, expected := range map[string]bool{"1.output": true, "3.output": false} {
+		exists, err := w.Exists(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exists != expected {
+			t.Errorf("Expected get %v, but got %v", expected, exists)
+		}
+	}
+
+	day := func(d int) time.Time {
+		return time.Date(2021, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+	report := types.Report{
+		From:                  day(1),
+		To:                    day(5),
+		Unit:                  "Megabits per second",
+		UnitExponent:          2,
+		Statistics:            types.Statistics{Count: 5, Min: 10, Max: 1000000, Mean: 800002},
+		UnderPerformingRanges: []types.Period{{From: day(2), To: day(2), Days: 1}, {From: day(4), To: day(5), Days: 2}},
+	}
+	err = w.WriteReport("1", report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.UnderPerformingRanges = []types.Period{{From: day(4), To: day(5), Days: 2}}
+	err = w.WriteReport("1", report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var periodTo int64
+	var mean float64
+	db.QueryRow("SELECT period_to, mean, count FROM results WHERE name = '1'").Scan(&periodTo, &mean, &count)
+	if periodTo != day(5).UnixNano() || mean != 800002 || count != 5 {
+		t.Errorf("Expected get %d with mean 800002 of 5, but got %d with mean %v of %d", day(5).UnixNano(), periodTo, mean, count)
+	}
+
+	periods := make([]types.Period, 0)
+	rows, err := db.Query("SELECT period_from, period_to, days FROM under_performing_periods WHERE name = '1' ORDER BY period_from")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var from, to int64
+		var days int
+		rows.Scan(&from, &to, &days)
+		periods = append(periods, types.Period{From: time.Unix(0, from).UTC(), To: time.Unix(0, to).UTC(), Days: days})
+	}
+	if len(periods) != 1 || !periods[0].From.Equal(day(4)) || !periods[0].To.Equal(day(5)) || periods[0].Days != 2 {
+		t.Errorf("Expected get [2021-01-04 to 2021-01-05 (2 days)], but got %v", periods)
+	}
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"math"
+	"time"
+)
+
+// summary statistics of a mesurement series (values are in bytes per second)
+type Statistics struct {
+	Count         int     `json:"count"`
+	Min           float64 `json:"min"`
+	Max           float64 `json:"max"`
+	Mean          float64 `json:"mean"`
+	Median        float64 `json:"median"`
+	FirstQuartile float64 `json:"firstQuartile"`
+	IQR           float64 `json:"iqr"`
+}
+
+// statistics of the mesurements that fall into one calendar bucket (e.g. a week, a month or a day of week)
+type Bucket struct {
+	Label string `json:"label"`
+	Statistics
+	UnderPerformingCount int `json:"underPerformingCount"`
+}
+
+// buckets produced by one calendar aggregation, ordered chronologically (or Monday to Sunday for day of week)
+type Aggregation struct {
+	Name    string   `json:"name"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// comparison of mesurements inside and outside the daily peak hours window
+type PeakAnalysis struct {
+	Window    string     `json:"window"`
+	Timezone  string     `json:"timezone"`
+	Peak      Statistics `json:"peak"`
+	OffPeak   Statistics `json:"offPeak"`
+	Threshold float64    `json:"threshold"`
+	// peak average divided by off-peak average, only meaningful when RatioAvailable (0 is a complete outage during peak hours)
+	Ratio float64 `json:"ratio"`
+	// false when either side has no mesurement or off-peak average is 0
+	RatioAvailable  bool `json:"ratioAvailable"`
+	UnderPerforming bool `json:"underPerforming"`
+}
+
+// continuous range of under-performing dates
+type Period struct {
+	// start of the first and the last date of the period, in timezone that input is analysed in (the same for period of one date)
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// number of calendar dates in the period
+	Days int `json:"days"`
+}
+
+// structured result of analysing one input, consumed by report renderer (values are in bytes per second, renderer convert them with Scale)
+type Report struct {
+	Name                   string     `json:"name"`
+	From                   time.Time  `json:"from"`
+	To                     time.Time  `json:"to"`
+	Unit                   string     `json:"unit"`
+	UnitExponent           int        `json:"unitExponent"`
+	Statistics             Statistics `json:"statistics"`
+	UnderPerformingPeriods []string   `json:"underPerformingPeriods"`
+	// the same periods as UnderPerformingPeriods for program consuming the report, so display text need not be parsed
+	UnderPerformingRanges []Period      `json:"underPerformingRanges"`
+	UnderPerformingDays   int           `json:"underPerformingDays"` // distinct days in UnderPerformingPeriods
+	Aggregations          []Aggregation `json:"aggregations"`
+	// nil when peak hours analysis is disabled
+	Peak *PeakAnalysis `json:"peak,omitempty"`
+}
+
+// convert value in bytes per second to the unit chosen for the report
+func (r Report) Scale(value float64) float64 {
+	// exact powers of 1000 for known units, so math.Pow is not called for every rendered value
+	if r.UnitExponent >= 0 && r.UnitExponent < len(unitDivisors) {
+		return value * 8 / unitDivisors[r.UnitExponent]
+	}
+	return value * 8 / math.Pow(1000, float64(r.UnitExponent))
+}
+
+var unitDivisors = [...]float64{1, 1e3, 1e6, 1e9, 1e12, 1e15}
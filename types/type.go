@@ -2,19 +2,48 @@ package types
 
 import "time"
 
-// type for decoding date from JSON to time.Time
+// layouts accepted for dtime, date only value is kept for the original daily mesurement (only RFC3339 carry UTC offset)
+var jsonTimeLayouts = []string{
+	`"2006-01-02"`,
+	`"` + time.RFC3339Nano + `"`,
+	`"2006-01-02T15:04:05"`,
+}
+
+// type for decoding date (or timestamp with time of day) from JSON to time.Time
 type JSONTime struct {
 	time.Time
+	// value has no UTC offset in source (date only or timestamp without offset) so it is wall clock time of the timezone it is analysed in
+	WallClock bool
 }
 
 func (t *JSONTime) UnmarshalJSON(b []byte) (err error) {
-	date, err := time.Parse(`"2006-01-02"`, string(b))
-	if err != nil {
-		return err
+	for _, layout := range jsonTimeLayouts {
+		date, parseErr := time.Parse(layout, string(b))
+		if parseErr == nil {
+			t.Time = date
+			t.WallClock = layout != jsonTimeLayouts[1]
+			return nil
+		}
+
+		// report the error of date only layout as it is the documented format
+		if err == nil {
+			err = parseErr
+		}
 	}
 
-	t.Time = date
-	return nil
+	return err
+}
+
+// function to convert the time into location, wall clock time keep its date and time of day
+func (t JSONTime) Localise(location *time.Location) JSONTime {
+	if t.WallClock {
+		return JSONTime{
+			Time:      time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), location),
+			WallClock: true,
+		}
+	}
+
+	return JSONTime{Time: t.Time.In(location)}
 }
 
 type Mesurement struct {
@@ -27,6 +56,12 @@ type InputFormat struct {
 	Content []Mesurement
 }
 
+// undecoded content of input
+type RawInput struct {
+	Name    string
+	Content []byte
+}
+
 type OutputFormat struct {
 	Name    string
 	Content []byte
@@ -0,0 +1,129 @@
+package types
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJSONTimeUnmarshalJSON(t *testing.T) {
+	type testcase struct {
+		name      string
+		input     string
+		result    time.Time
+		wallClock bool
+		err       bool
+	}
+
+	testcases := []testcase{
+		{
+			name:      "Date",
+			input:     `"2021-02-03"`,
+			result:    time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC),
+			wallClock: true,
+		},
+		{
+			name:   "RFC3339",
+			input:  `"2021-02-03T20:15:00+01:00"`,
+			result: time.Date(2021, 2, 3, 19, 15, 0, 0, time.UTC),
+		},
+		{
+			name:      "Without offset",
+			input:     `"2021-02-03T20:15:00"`,
+			result:    time.Date(2021, 2, 3, 20, 15, 0, 0, time.UTC),
+			wallClock: true,
+		},
+		{
+			name:  "Invalid",
+			input: `"03/02/2021"`,
+			err:   true,
+		},
+		{
+			name:  "Number",
+			input: `20210203`,
+			err:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var result JSONTime
+			err := json.Unmarshal([]byte(tc.input), &result)
+			if (err != nil) != tc.err {
+				t.Fatalf("Expected error %v, but got %v", tc.err, err)
+			}
+			if !result.Equal(tc.result) {
+				t.Errorf("Expected get %v, but got %v", tc.result, result.Time)
+			}
+			if result.WallClock != tc.wallClock {
+				t.Errorf("Expected get %v, but got %v", tc.wallClock, result.WallClock)
+			}
+		})
+	}
+}
+
+// function to add dtime of every real input file as seed
+func addDtimeSeeds(f *testing.F) {
+	files, err := filepath.Glob("../app/testdata/corpus/input/*.json")
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		var rows []struct {
+			Dtime json.RawMessage `json:"dtime"`
+		}
+		err = json.Unmarshal(content, &rows)
+		if err != nil {
+			f.Fatal(err)
+		}
+		for _, row := range rows {
+			f.Add(string(row.Dtime))
+		}
+	}
+}
+
+func FuzzJSONTimeUnmarshalJSON(f *testing.F) {
+	addDtimeSeeds(f)
+	for _, seed := range []string{`"2021-02-03"`, `"2021-02-03T20:15:00+01:00"`, `"2021-02-03T20:15:00.123456789Z"`, `"2021-02-03T20:15:00"`, `"2021-02-30"`, `"2021-02-03 20:15"`, `null`, `""`, `"`} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var result JSONTime
+		err := result.UnmarshalJSON([]byte(input))
+		if err != nil {
+			return
+		}
+
+		// value without offset is wall clock time kept in UTC until it is localised
+		if result.WallClock && result.Location() != time.UTC {
+			t.Errorf("Expected get %v, but got %v of %s", time.UTC, result.Location(), input)
+		}
+
+		// same instant is decoded again from its RFC3339 form, which is never wall clock time
+		var again JSONTime
+		err = again.UnmarshalJSON([]byte(`"` + result.Format(time.RFC3339Nano) + `"`))
+		if err != nil {
+			t.Fatalf("Expected nil of %s, but got %v", result.Format(time.RFC3339Nano), err)
+		}
+		if !again.Equal(result.Time) || again.WallClock {
+			t.Errorf("Expected get %v, but got %v (wall clock %v) of %s", result.Time, again.Time, again.WallClock, input)
+		}
+
+		// localising wall clock time keep its date and time of day
+		zone := time.FixedZone("UTC+1", 3600)
+		localised := result.Localise(zone)
+		if result.WallClock && localised.Format("2006-01-02T15:04:05.999999999") != result.Format("2006-01-02T15:04:05.999999999") {
+			t.Errorf("Expected get %v, but got %v of %s", result.Time, localised.Time, input)
+		}
+		if !result.WallClock && !localised.Equal(result.Time) {
+			t.Errorf("Expected get %v, but got %v of %s", result.Time, localised.Time, input)
+		}
+	})
+}
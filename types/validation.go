@@ -0,0 +1,26 @@
+package types
+
+// problem found when validating input, Line and Column are 1-based (0 when position is unknown)
+type ValidationIssue struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// validation result of one input
+type FileValidation struct {
+	Name        string            `json:"name"`
+	Valid       bool              `json:"valid"`
+	Mesurements int               `json:"measurements"`
+	Errors      []ValidationIssue `json:"errors"`
+	Warnings    []ValidationIssue `json:"warnings"`
+}
+
+// machine-readable summary of validating all inputs
+type ValidationSummary struct {
+	Valid    bool             `json:"valid"`
+	Files    []FileValidation `json:"files"`
+	Errors   int              `json:"errors"`
+	Warnings int              `json:"warnings"`
+}